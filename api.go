@@ -0,0 +1,26 @@
+package switchbot
+
+import "context"
+
+// API is the set of SwitchBot API operations implemented by *Client.
+// Depend on API instead of *Client to substitute a mock in tests.
+type API interface {
+	// Devices
+	GetDevices(ctx context.Context) (*GetDevicesResponse, error)
+	GetDeviceStatus(ctx context.Context, deviceID string) (DeviceStatus, error)
+	SendDeviceCommand(ctx context.Context, deviceID string, command string, parameter interface{}, commandType string) (CommandResponse, error)
+
+	// Scenes
+	GetScenes(ctx context.Context) ([]Scene, error)
+	ExecuteScene(ctx context.Context, sceneID string) error
+
+	// Webhooks
+	SetupWebhook(ctx context.Context, webhookURL string) error
+	QueryWebhookURL(ctx context.Context) ([]string, error)
+	QueryWebhookDetails(ctx context.Context, urls []string) ([]WebhookDetails, error)
+	UpdateWebhook(ctx context.Context, webhookURL string, enable bool) error
+	DeleteWebhook(ctx context.Context, webhookURL string) error
+}
+
+// Compile-time check that *Client implements API.
+var _ API = (*Client)(nil)
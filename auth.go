@@ -1,28 +1,172 @@
 package switchbot
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 )
 
-func (c *Client) setAuthorizationHeader(req *http.Request) {
+// defaultCredentialsTTL is how long credentials returned by a CredentialsProvider are cached.
+const defaultCredentialsTTL = 30 * time.Second
+
+// CredentialsProvider returns the current token and secret.
+// It allows credentials to be rotated without recreating the Client.
+type CredentialsProvider func(ctx context.Context) (token, secret string, err error)
+
+// SigningInfo records the values used to sign one request, for audit logging.
+// It deliberately excludes the signature and the secret.
+type SigningInfo struct {
+	Method    string // HTTP method
+	Path      string // Request path, e.g. "/v1.1/devices"
+	Timestamp string // The "t" header: milliseconds since the Unix epoch
+	Nonce     string // The "nonce" header
+	TraceID   string // Trace ID from WithTraceID, if any
+	_         struct{}
+}
+
+// Time returns Timestamp as a time.Time, or the zero time if it cannot be parsed.
+func (s SigningInfo) Time() time.Time {
+	ms, err := strconv.ParseInt(s.Timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// WithSigningObserver registers a callback invoked with the nonce and timestamp of every signed
+// request just before it is sent, including retries. The callback runs synchronously on the
+// request goroutine, so it should return quickly.
+func WithSigningObserver(fn func(SigningInfo)) ClientOption {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("signing observer cannot be nil")
+		}
+		c.signingObserver = fn
+		return nil
+	}
+}
+
+// WithSigner replaces the function that computes the "sign" header, e.g. if SwitchBot changes the
+// signature algorithm, or to make signatures predictable in tests. fn receives the secret and the
+// payload token+timestamp+nonce, where timestamp is the "t" header and nonce the "nonce" header,
+// and returns the header value. The default is the base64-encoded HMAC-SHA256 used by SignRequest.
+func WithSigner(fn func(secret, payload string) string) ClientOption {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("signer cannot be nil")
+		}
+		c.signer = fn
+		return nil
+	}
+}
+
+// SignRequest computes the "sign" header for a request: the base64-encoded HMAC-SHA256 of
+// token+timestamp+nonce keyed with secret. timestamp is the "t" header (milliseconds since the
+// Unix epoch) and nonce the "nonce" header. The client signs every request with it unless
+// WithSigner is used.
+func SignRequest(token, secret, timestamp, nonce string) string {
+	return signHMACSHA256(secret, token+timestamp+nonce)
+}
+
+// signHMACSHA256 is the default signer: the base64-encoded HMAC-SHA256 of payload keyed with secret.
+func signHMACSHA256(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// setAuthorizationHeader signs req and returns the timestamp and nonce it used.
+func (c *Client) setAuthorizationHeader(req *http.Request) (SigningInfo, error) {
+	token, secret, err := c.credentials(req.Context())
+	if err != nil {
+		return SigningInfo{}, err
+	}
+
 	t := generateTimestamp()
 	n := generateNonce()
 
-	mac := hmac.New(sha256.New, []byte(c.secret))
-	mac.Write([]byte(c.token + t + n))
-	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	signature := c.signer(secret, token+t+n) // The payload is token+timestamp+nonce
 
 	header := req.Header
-	header.Set("Authorization", c.token)
+	header.Set("Authorization", token)
 	header.Set("t", t)
 	header.Set("sign", signature)
 	header.Set("nonce", n)
-	header.Set("Content-Type", "application/json; charset=utf-8")
+	if req.Method != http.MethodGet || c.contentTypeOnGet {
+		header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	return SigningInfo{
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		Timestamp: t,
+		Nonce:     n,
+		TraceID:   TraceIDFromContext(req.Context()),
+	}, nil
+}
+
+// credentialsFetch is a call to the CredentialsProvider shared by concurrent requests.
+// done is closed once token, secret and err are set.
+type credentialsFetch struct {
+	done          chan struct{}
+	token, secret string
+	err           error
+}
+
+// credentials returns the token and secret to sign a request with.
+// Values from a CredentialsProvider are cached for credentialsTTL. The provider runs without
+// credMu held; requests arriving while it runs wait for that call instead of starting another.
+func (c *Client) credentials(ctx context.Context) (string, string, error) {
+	if c.credentialsProvider == nil {
+		return c.token, c.secret, nil
+	}
+
+	c.credMu.Lock()
+	if time.Now().Before(c.cachedCredsExpiry) {
+		token, secret := c.cachedToken, c.cachedSecret
+		c.credMu.Unlock()
+		return token, secret, nil
+	}
+	if fetch := c.credFetch; fetch != nil {
+		c.credMu.Unlock()
+		select {
+		case <-fetch.done:
+			return fetch.token, fetch.secret, fetch.err
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		}
+	}
+	fetch := &credentialsFetch{done: make(chan struct{})}
+	c.credFetch = fetch
+	c.credMu.Unlock()
+
+	token, secret, err := c.credentialsProvider(ctx)
+	switch {
+	case err != nil:
+		err = fmt.Errorf("failed to get credentials: %w", err)
+	case token == "" || secret == "":
+		err = fmt.Errorf("failed to get credentials: token and secret must not be empty")
+	}
+	if err != nil {
+		token, secret = "", ""
+	}
+
+	c.credMu.Lock()
+	if err == nil {
+		c.cachedToken = token
+		c.cachedSecret = secret
+		c.cachedCredsExpiry = time.Now().Add(c.credentialsTTL)
+	}
+	c.credFetch = nil
+	fetch.token, fetch.secret, fetch.err = token, secret, err
+	close(fetch.done)
+	c.credMu.Unlock()
+
+	return token, secret, err
 }
 
 // generateTimestamp generates a timestamp in milliseconds since epoch.
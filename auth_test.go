@@ -1,13 +1,18 @@
 package switchbot
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -28,7 +33,9 @@ func TestSetAuthorizationHeader(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
 
 	// Call the function to test
-	client.setAuthorizationHeader(req)
+	if _, err := client.setAuthorizationHeader(req); err != nil {
+		t.Fatalf("setAuthorizationHeader() returned error: %v", err)
+	}
 
 	// --- Assertions ---
 	t.Run("Check Authorization Header", func(t *testing.T) {
@@ -101,6 +108,165 @@ func TestSetAuthorizationHeader(t *testing.T) {
 	})
 }
 
+func TestSignRequest(t *testing.T) {
+	// Reference value computed independently with Python's hmac module.
+	const want = "HFL6ot1FXJd3TFFsK5dzb6VckQ86p3qeJTA7Gil5CmU="
+	got := SignRequest("my-token", "my-secret", "1700000000000", "a6f8e2b1-1c3d-7e4f-8a9b-0c1d2e3f4a5b")
+	if got != want {
+		t.Errorf("SignRequest() = %q; want %q", got, want)
+	}
+
+	client, err := NewClient("my-token", "my-secret")
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/v1.1/devices", nil)
+	if _, err := client.setAuthorizationHeader(req); err != nil {
+		t.Fatalf("setAuthorizationHeader() returned error: %v", err)
+	}
+	if sign := SignRequest("my-token", "my-secret", req.Header.Get("t"), req.Header.Get("nonce")); req.Header.Get("sign") != sign {
+		t.Errorf("sign header = %q; want SignRequest() = %q", req.Header.Get("sign"), sign)
+	}
+}
+
+func TestWithSigner(t *testing.T) {
+	client, err := NewClient("my-token", "my-secret", WithSigner(func(secret, payload string) string {
+		return secret + "|" + payload
+	}))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/v1.1/devices", nil)
+	if _, err := client.setAuthorizationHeader(req); err != nil {
+		t.Fatalf("setAuthorizationHeader() returned error: %v", err)
+	}
+	want := "my-secret|my-token" + req.Header.Get("t") + req.Header.Get("nonce")
+	if got := req.Header.Get("sign"); got != want {
+		t.Errorf("sign header = %q; want %q", got, want)
+	}
+
+	if _, err := NewClient("my-token", "my-secret", WithSigner(nil)); err == nil {
+		t.Error("NewClient() with a nil signer did not return an error")
+	}
+}
+
+func TestCredentialsProvider(t *testing.T) {
+	t.Run("ProviderConsultedAndCached", func(t *testing.T) {
+		calls := 0
+		provider := func(ctx context.Context) (string, string, error) {
+			calls++
+			return fmt.Sprintf("token-%d", calls), "rotated-secret", nil
+		}
+		client, err := NewClient("", "", WithCredentialsProvider(provider))
+		if err != nil {
+			t.Fatalf("NewClient() with provider returned error: %v", err)
+		}
+
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
+			if _, err := client.setAuthorizationHeader(req); err != nil {
+				t.Fatalf("setAuthorizationHeader() returned error: %v", err)
+			}
+			if got := req.Header.Get("Authorization"); got != "token-1" {
+				t.Errorf("Authorization header = %q; want %q", got, "token-1")
+			}
+		}
+		if calls != 1 {
+			t.Errorf("provider called %d times; want 1 (cached)", calls)
+		}
+
+	})
+
+	t.Run("ProviderConsultedAfterExpiry", func(t *testing.T) {
+		calls := 0
+		provider := func(ctx context.Context) (string, string, error) {
+			calls++
+			return fmt.Sprintf("token-%d", calls), "rotated-secret", nil
+		}
+		client, err := NewClient("", "", WithCredentialsProvider(provider), withCredentialsTTL(0))
+		if err != nil {
+			t.Fatalf("NewClient() with provider returned error: %v", err)
+		}
+
+		for i := 1; i <= 2; i++ {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
+			if _, err := client.setAuthorizationHeader(req); err != nil {
+				t.Fatalf("setAuthorizationHeader() returned error: %v", err)
+			}
+			if got, want := req.Header.Get("Authorization"), fmt.Sprintf("token-%d", i); got != want {
+				t.Errorf("Authorization header = %q; want %q", got, want)
+			}
+		}
+	})
+
+	t.Run("ConcurrentRequestsShareOneCall", func(t *testing.T) {
+		var calls int32
+		release := make(chan struct{})
+		provider := func(ctx context.Context) (string, string, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return "token", "secret", nil
+		}
+		client, err := NewClient("", "", WithCredentialsProvider(provider))
+		if err != nil {
+			t.Fatalf("NewClient() with provider returned error: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, _, err := client.credentials(context.Background()); err != nil {
+					t.Errorf("credentials() returned error: %v", err)
+				}
+			}()
+		}
+
+		// A waiter whose context ends returns without waiting for the slow provider
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for atomic.LoadInt32(&calls) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		if _, _, err := client.credentials(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("credentials() with canceled context error = %v; want %v", err, context.Canceled)
+		}
+
+		close(release)
+		wg.Wait()
+		if got := atomic.LoadInt32(&calls); got != 1 {
+			t.Errorf("provider called %d times; want 1", got)
+		}
+	})
+
+	t.Run("ProviderErrorPropagates", func(t *testing.T) {
+		providerErr := errors.New("vault unavailable")
+		provider := func(ctx context.Context) (string, string, error) {
+			return "", "", providerErr
+		}
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			t.Error("request should not be sent when credentials are unavailable")
+		}
+		_, server := setupMockServer(t, handler)
+		client, err := NewClient("", "", WithBaseURL(server.URL), WithCredentialsProvider(provider))
+		if err != nil {
+			t.Fatalf("NewClient() with provider returned error: %v", err)
+		}
+
+		_, err = client.GetDevices(context.Background())
+		if !errors.Is(err, providerErr) {
+			t.Errorf("GetDevices() error = %v; want wrapping %v", err, providerErr)
+		}
+	})
+
+	t.Run("NilProvider", func(t *testing.T) {
+		if _, err := NewClient("token", "secret", WithCredentialsProvider(nil)); err == nil {
+			t.Error("NewClient() with nil provider did not return an error")
+		}
+	})
+}
+
 func TestGenerateTimestamp(t *testing.T) {
 	before := time.Now().UnixMilli()
 	// Allow very brief execution time
@@ -150,3 +316,84 @@ func TestGenerateNonce(t *testing.T) {
 		}
 	})
 }
+
+func TestWithContentTypeOnGet(t *testing.T) {
+	contentTypes := make(map[string]string)
+	_, server := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		contentTypes[r.Method] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 100, "message": "success", "body": {}}`)
+	})
+	client, err := NewClient("token", "secret", WithBaseURL(server.URL), WithContentTypeOnGet(false))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	if _, err := client.GetDeviceStatus(context.Background(), "D1"); err != nil {
+		t.Fatalf("GetDeviceStatus() returned error: %v", err)
+	}
+	if _, err := client.SendDeviceCommand(context.Background(), "D1", "turnOn", nil, ""); err != nil {
+		t.Fatalf("SendDeviceCommand() returned error: %v", err)
+	}
+
+	if got, ok := contentTypes[http.MethodGet]; !ok || got != "" {
+		t.Errorf("GET Content-Type = %q; want it omitted", got)
+	}
+	if got := contentTypes[http.MethodPost]; got != "application/json; charset=utf-8" {
+		t.Errorf("POST Content-Type = %q; want application/json", got)
+	}
+}
+
+func TestWithSigningObserver(t *testing.T) {
+	if _, err := NewClient("token", "secret", WithSigningObserver(nil)); err == nil {
+		t.Error("WithSigningObserver(nil) did not return an error")
+	}
+
+	var headers []http.Header
+	_, server := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 100, "message": "success", "body": {}}`)
+	})
+	var infos []SigningInfo
+	client, err := NewClient("token", "secret", WithBaseURL(server.URL), WithSigningObserver(func(info SigningInfo) {
+		infos = append(infos, info)
+	}))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	ctx := WithTraceID(context.Background(), "trace-1")
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetDeviceStatus(ctx, "D1"); err != nil {
+			t.Fatalf("GetDeviceStatus() returned error: %v", err)
+		}
+	}
+
+	if len(infos) != 2 || len(headers) != 2 {
+		t.Fatalf("observed %d signings for %d requests; want 2", len(infos), len(headers))
+	}
+	for i, info := range infos {
+		if info.Nonce != headers[i].Get("nonce") || info.Timestamp != headers[i].Get("t") {
+			t.Errorf("signing %d = {t: %s, nonce: %s}; want the sent headers {t: %s, nonce: %s}",
+				i, info.Timestamp, info.Nonce, headers[i].Get("t"), headers[i].Get("nonce"))
+		}
+		if info.Method != http.MethodGet || info.Path != "/v1.1/devices/D1/status" || info.TraceID != "trace-1" {
+			t.Errorf("signing %d = %+v; want GET /v1.1/devices/D1/status with trace-1", i, info)
+		}
+		if d := time.Since(info.Time()); d < 0 || d > time.Minute {
+			t.Errorf("signing %d Time() = %v; want about now", i, info.Time())
+		}
+	}
+	if infos[0].Nonce == infos[1].Nonce {
+		t.Error("requests were signed with the same nonce")
+	}
+}
+
+// withCredentialsTTL overrides how long provider credentials are cached.
+func withCredentialsTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.credentialsTTL = ttl
+		return nil
+	}
+}
@@ -0,0 +1,92 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+)
+
+// TiltDirection is the direction a Blind Tilt's slats are tilted towards.
+type TiltDirection string
+
+const (
+	TiltDirectionUp   TiltDirection = "up"
+	TiltDirectionDown TiltDirection = "down"
+)
+
+// String implements fmt.Stringer.
+func (d TiltDirection) String() string {
+	switch d {
+	case TiltDirectionUp:
+		return "up"
+	case TiltDirectionDown:
+		return "down"
+	case "":
+		return "unknown"
+	}
+	return string(d)
+}
+
+// BlindTiltStatus represents the status of a Blind Tilt.
+type BlindTiltStatus struct {
+	reportedStatus // Provides ReportedAt
+
+	DeviceID      string        `json:"deviceId"`
+	DeviceType    string        `json:"deviceType"`
+	HubDeviceID   string        `json:"hubDeviceId"`
+	Version       string        `json:"version"`
+	Calibrate     bool          `json:"calibrate"`
+	Group         bool          `json:"group"`
+	Moving        bool          `json:"moving"`
+	Direction     TiltDirection `json:"direction"`
+	SlidePosition int           `json:"slidePosition"` // 0 (closed) to 100 (open)
+	Battery       FlexInt       `json:"battery"`       // Percentage (0-100)
+	_             struct{}
+}
+
+// IsFullyOpen reports whether the slats are fully open (position 100), in either direction.
+// This matches the fullyOpen command, which is equivalent to "up;100" or "down;100".
+func (s *BlindTiltStatus) IsFullyOpen() bool {
+	return s.SlidePosition >= 100
+}
+
+// IsClosed reports whether the slats are fully closed (position 0), whichever way they face.
+func (s *BlindTiltStatus) IsClosed() bool {
+	return s.SlidePosition <= 0
+}
+
+// IsClosedUp reports whether the slats are closed facing up, as set by the closeUp command ("up;0").
+func (s *BlindTiltStatus) IsClosedUp() bool {
+	return s.IsClosed() && s.Direction == TiltDirectionUp
+}
+
+// IsClosedDown reports whether the slats are closed facing down, as set by the closeDown command ("down;0").
+func (s *BlindTiltStatus) IsClosedDown() bool {
+	return s.IsClosed() && s.Direction == TiltDirectionDown
+}
+
+// GetBlindTiltStatus retrieves the typed status of a Blind Tilt.
+// Returns ErrDeviceTypeMismatch if the device is not a Blind Tilt.
+func (c *Client) GetBlindTiltStatus(ctx context.Context, deviceID string) (*BlindTiltStatus, error) {
+	var status BlindTiltStatus
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, DeviceTypeBlindTilt); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SetBlindTiltPosition tilts the slats towards direction to the given position.
+// The API requires position to be an even number from 0 to 100.
+func (c *Client) SetBlindTiltPosition(ctx context.Context, deviceID string, direction TiltDirection, position int) error {
+	if direction != TiltDirectionUp && direction != TiltDirectionDown {
+		return fmt.Errorf("%w: blind tilt direction %q must be up or down", ErrInvalidParameter, string(direction))
+	}
+	if err := checkRange("blind tilt position", position, 0, 100); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	if position%2 != 0 {
+		return fmt.Errorf("%w: blind tilt position %d must be an even number", ErrInvalidParameter, position)
+	}
+	parameter := fmt.Sprintf("%s;%d", string(direction), position)
+	_, err := c.SendDeviceCommand(ctx, deviceID, "setPosition", parameter, "")
+	return err
+}
@@ -0,0 +1,81 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetBlindTiltStatus(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(`{"deviceId": "T1", "deviceType": "Blind Tilt", "hubDeviceId": "H1", "version": "V2.0", "calibrate": true, "group": false, "moving": false, "direction": "down", "slidePosition": 40, "battery": 77}`))
+
+	status, err := client.GetBlindTiltStatus(context.Background(), "T1")
+	if err != nil {
+		t.Fatalf("GetBlindTiltStatus() returned error: %v", err)
+	}
+	if status.Direction != TiltDirectionDown || status.SlidePosition != 40 || status.Battery != 77 {
+		t.Errorf("status not decoded: %+v", *status)
+	}
+}
+
+func TestSetBlindTiltPosition(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+	ctx := context.Background()
+
+	if err := client.SetBlindTiltPosition(ctx, "T1", TiltDirectionUp, 60); err != nil {
+		t.Fatalf("SetBlindTiltPosition() returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].Command != "setPosition" || got[0].Parameter != "up;60" {
+		t.Errorf("received %+v; want setPosition(up;60)", got)
+	}
+
+	err := client.SetBlindTiltPosition(ctx, "T1", TiltDirectionDown, 61)
+	if !errors.Is(err, ErrInvalidParameter) || !strings.Contains(err.Error(), "even") {
+		t.Errorf("SetBlindTiltPosition(61) error = %v; want ErrInvalidParameter mentioning even", err)
+	}
+	if err := client.SetBlindTiltPosition(ctx, "T1", TiltDirectionDown, 102); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("SetBlindTiltPosition(102) error = %v; want ErrInvalidParameter", err)
+	}
+	if err := client.SetBlindTiltPosition(ctx, "T1", "left", 50); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("SetBlindTiltPosition(left) error = %v; want ErrInvalidParameter", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("sent %d commands; invalid values should not be sent", len(got))
+	}
+}
+
+func TestBlindTiltStatus_OpenClosed(t *testing.T) {
+	testCases := []struct {
+		direction      TiltDirection
+		position       int
+		wantOpen       bool
+		wantClosed     bool
+		wantClosedUp   bool
+		wantClosedDown bool
+	}{
+		{TiltDirectionUp, 100, true, false, false, false},
+		{TiltDirectionDown, 100, true, false, false, false},
+		{TiltDirectionUp, 0, false, true, true, false},
+		{TiltDirectionDown, 0, false, true, false, true},
+		{TiltDirectionUp, 50, false, false, false, false},
+		{TiltDirectionDown, 2, false, false, false, false},
+	}
+
+	for _, tc := range testCases {
+		status := BlindTiltStatus{Direction: tc.direction, SlidePosition: tc.position}
+		if got := status.IsFullyOpen(); got != tc.wantOpen {
+			t.Errorf("%s;%d IsFullyOpen() = %v; want %v", tc.direction, tc.position, got, tc.wantOpen)
+		}
+		if got := status.IsClosed(); got != tc.wantClosed {
+			t.Errorf("%s;%d IsClosed() = %v; want %v", tc.direction, tc.position, got, tc.wantClosed)
+		}
+		if got := status.IsClosedUp(); got != tc.wantClosedUp {
+			t.Errorf("%s;%d IsClosedUp() = %v; want %v", tc.direction, tc.position, got, tc.wantClosedUp)
+		}
+		if got := status.IsClosedDown(); got != tc.wantClosedDown {
+			t.Errorf("%s;%d IsClosedDown() = %v; want %v", tc.direction, tc.position, got, tc.wantClosedDown)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrBotPressMode is returned by TurnOnBot and TurnOffBot when the mode check finds the Bot in press mode.
+var ErrBotPressMode = errors.New("bot is in press mode")
+
+// BotMode is the operating mode of a Bot.
+type BotMode string
+
+const (
+	BotModePress     BotMode = "pressMode"     // Only press is meaningful
+	BotModeSwitch    BotMode = "switchMode"    // turnOn/turnOff toggle between two positions
+	BotModeCustomize BotMode = "customizeMode" // Configured in the SwitchBot app
+)
+
+// String implements fmt.Stringer. It returns the wire value, e.g. "pressMode".
+func (m BotMode) String() string {
+	if m == "" {
+		return "unknown"
+	}
+	return string(m)
+}
+
+// BotStatus represents the status of a Bot.
+type BotStatus struct {
+	reportedStatus // Provides ReportedAt
+
+	DeviceID    string     `json:"deviceId"`
+	DeviceType  string     `json:"deviceType"`
+	HubDeviceID string     `json:"hubDeviceId"`
+	Version     string     `json:"version"`
+	Power       PowerState `json:"power"`
+	Battery     FlexInt    `json:"battery"` // Percentage (0-100)
+	DeviceMode  BotMode    `json:"deviceMode"`
+	_           struct{}
+}
+
+// GetBotStatus retrieves the typed status of a Bot.
+// Returns ErrDeviceTypeMismatch if the device is not a Bot.
+func (c *Client) GetBotStatus(ctx context.Context, deviceID string) (*BotStatus, error) {
+	var status BotStatus
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, DeviceTypeBot); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// PressBot presses the Bot's arm once.
+func (c *Client) PressBot(ctx context.Context, deviceID string) error {
+	_, err := c.SendDeviceCommand(ctx, deviceID, "press", nil, "")
+	return err
+}
+
+// TurnOnBot turns a Bot in switch mode on. If checkMode is true, the Bot's mode is read first
+// (one extra API call) and ErrBotPressMode is returned if it is in press mode.
+func (c *Client) TurnOnBot(ctx context.Context, deviceID string, checkMode bool) error {
+	return c.switchBot(ctx, deviceID, "turnOn", checkMode)
+}
+
+// TurnOffBot turns a Bot in switch mode off. If checkMode is true, the Bot's mode is read first
+// (one extra API call) and ErrBotPressMode is returned if it is in press mode.
+func (c *Client) TurnOffBot(ctx context.Context, deviceID string, checkMode bool) error {
+	return c.switchBot(ctx, deviceID, "turnOff", checkMode)
+}
+
+func (c *Client) switchBot(ctx context.Context, deviceID, command string, checkMode bool) error {
+	if checkMode {
+		status, err := c.GetBotStatus(ctx, deviceID)
+		if err != nil {
+			return err
+		}
+		if status.DeviceMode == BotModePress {
+			return fmt.Errorf("%w: cannot %s bot %s, use PressBot instead", ErrBotPressMode, command, deviceID)
+		}
+	}
+	_, err := c.SendDeviceCommand(ctx, deviceID, command, nil, "")
+	return err
+}
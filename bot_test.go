@@ -0,0 +1,85 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// botHandler serves a Bot status in the given mode for GET requests and captures commands.
+func botHandler(t *testing.T, mode BotMode, got *[]capturedCommand) http.HandlerFunc {
+	capture := commandCaptureHandler(t, got)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			statusHandler(fmt.Sprintf(`{"deviceId": "B1", "deviceType": "Bot", "power": "off", "battery": 90, "deviceMode": %q}`, string(mode)))(w, r)
+			return
+		}
+		capture(w, r)
+	}
+}
+
+func TestGetBotStatus(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, botHandler(t, BotModeSwitch, &got))
+
+	status, err := client.GetBotStatus(context.Background(), "B1")
+	if err != nil {
+		t.Fatalf("GetBotStatus() returned error: %v", err)
+	}
+	if status.DeviceMode != BotModeSwitch || status.Power != PowerStateOff || status.Battery != 90 {
+		t.Errorf("GetBotStatus() = %+v", *status)
+	}
+}
+
+func TestBotCommands(t *testing.T) {
+	t.Run("Press", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, botHandler(t, BotModePress, &got))
+
+		if err := client.PressBot(context.Background(), "B1"); err != nil {
+			t.Fatalf("PressBot() returned error: %v", err)
+		}
+		if len(got) != 1 || got[0].Command != "press" {
+			t.Errorf("commands = %+v; want press", got)
+		}
+	})
+
+	t.Run("TurnOnWithoutCheck", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, botHandler(t, BotModePress, &got))
+
+		if err := client.TurnOnBot(context.Background(), "B1", false); err != nil {
+			t.Fatalf("TurnOnBot() returned error: %v", err)
+		}
+		if len(got) != 1 || got[0].Command != "turnOn" {
+			t.Errorf("commands = %+v; want turnOn", got)
+		}
+	})
+
+	t.Run("TurnOffSwitchMode", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, botHandler(t, BotModeSwitch, &got))
+
+		if err := client.TurnOffBot(context.Background(), "B1", true); err != nil {
+			t.Fatalf("TurnOffBot() returned error: %v", err)
+		}
+		if len(got) != 1 || got[0].Command != "turnOff" {
+			t.Errorf("commands = %+v; want turnOff", got)
+		}
+	})
+
+	t.Run("TurnOnPressMode", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, botHandler(t, BotModePress, &got))
+
+		err := client.TurnOnBot(context.Background(), "B1", true)
+		if !errors.Is(err, ErrBotPressMode) {
+			t.Fatalf("TurnOnBot() error = %v; want ErrBotPressMode", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("sent %d commands; want none", len(got))
+		}
+	})
+}
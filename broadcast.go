@@ -0,0 +1,62 @@
+package switchbot
+
+import (
+	"context"
+	"sync"
+)
+
+// BroadcastCommand sends the same command to each device with up to maxConcurrency requests in flight.
+// It returns the error for every device, keyed by device ID; a nil value means the command succeeded.
+func (c *Client) BroadcastCommand(ctx context.Context, deviceIDs []string, command string, parameter interface{}, commandType string, maxConcurrency int) map[string]error {
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]error, len(deviceIDs))
+		sem     = make(chan struct{}, maxConcurrency)
+	)
+	for _, id := range deviceIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_, err := c.SendDeviceCommand(ctx, id, command, parameter, commandType)
+			mu.Lock()
+			results[id] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// TurnOffAllLights turns off every light in the account: Color Bulbs, Strip Lights, Ceiling Lights
+// and infrared Light remotes (see IsLight). "DIY Light" remotes are skipped, since their learned
+// buttons are sent as customize commands and need not include turnOff. It returns the result for
+// each light as BroadcastCommand does.
+// If the device list cannot be fetched, the error is returned under the "" key.
+func (c *Client) TurnOffAllLights(ctx context.Context, maxConcurrency int) map[string]error {
+	devicesResp, err := c.GetDevices(ctx)
+	if err != nil {
+		return map[string]error{"": err}
+	}
+
+	var lightIDs []string
+	for _, d := range devicesResp.DeviceList {
+		deviceType, _ := d["deviceType"].(string)
+		id, _ := d["deviceId"].(string)
+		if id != "" && IsLight(deviceType) {
+			lightIDs = append(lightIDs, id)
+		}
+	}
+	for _, ir := range devicesResp.InfraredRemoteList {
+		if ir.DeviceID != "" && IsLight(ir.RemoteType) && !ir.Type().IsDIY() {
+			lightIDs = append(lightIDs, ir.DeviceID)
+		}
+	}
+	return c.BroadcastCommand(ctx, lightIDs, "turnOff", nil, "", maxConcurrency)
+}
@@ -0,0 +1,84 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestBroadcastCommand(t *testing.T) {
+	var got []capturedCommand
+	capture := commandCaptureHandler(t, &got)
+	client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/OFFLINE/") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"statusCode": 161, "message": "device offline", "body": {}}`))
+			return
+		}
+		capture(w, r)
+	})
+
+	results := client.BroadcastCommand(context.Background(), []string{"A", "B", "OFFLINE"}, "turnOn", nil, "", 2)
+	if len(results) != 3 {
+		t.Fatalf("got %d results; want 3", len(results))
+	}
+	if results["A"] != nil || results["B"] != nil {
+		t.Errorf("results = %v; want A and B to succeed", results)
+	}
+	if results["OFFLINE"] == nil {
+		t.Error("results[OFFLINE] = nil; want error")
+	}
+	if len(got) != 2 {
+		t.Errorf("sent %d commands; want 2", len(got))
+	}
+}
+
+func TestTurnOffAllLights(t *testing.T) {
+	var got []capturedCommand
+	capture := commandCaptureHandler(t, &got)
+	client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			statusHandler(`{
+				"deviceList": [
+					{"deviceId": "BULB", "deviceType": "Color Bulb"},
+					{"deviceId": "STRIP", "deviceType": "Strip Light"},
+					{"deviceId": "CEIL", "deviceType": "Ceiling Light"},
+					{"deviceId": "BOT", "deviceType": "Bot"},
+					{"deviceId": "LOCK", "deviceType": "Smart Lock"}
+				],
+				"infraredRemoteList": [
+					{"deviceId": "IRLIGHT", "remoteType": "Light"},
+					{"deviceId": "IRDIYLIGHT", "remoteType": "DIY Light"},
+					{"deviceId": "IRTV", "remoteType": "TV"}
+				]
+			}`)(w, r)
+			return
+		}
+		capture(w, r)
+	})
+
+	results := client.TurnOffAllLights(context.Background(), 3)
+
+	var targeted []string
+	for id, err := range results {
+		if err != nil {
+			t.Errorf("results[%s] = %v", id, err)
+		}
+		targeted = append(targeted, id)
+	}
+	sort.Strings(targeted)
+	// IRDIYLIGHT is skipped: DIY remotes only accept their learned buttons
+	if want := "BULB,CEIL,IRLIGHT,STRIP"; strings.Join(targeted, ",") != want {
+		t.Errorf("targeted %v; want %s", targeted, want)
+	}
+	for _, cmd := range got {
+		if cmd.Command != "turnOff" {
+			t.Errorf("command = %q; want turnOff", cmd.Command)
+		}
+	}
+	if len(got) != 4 {
+		t.Errorf("sent %d commands; want 4", len(got))
+	}
+}
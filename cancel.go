@@ -0,0 +1,49 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrCancelNotSupported is returned by CancelCommand for device types with no stop command.
+var ErrCancelNotSupported = errors.New("cancel not supported for device type")
+
+// stopCommands maps device types to the command that halts an in-progress movement or job.
+var stopCommands = map[string]string{
+	DeviceTypeCurtain:            "pause",
+	DeviceTypeCurtain3:           "pause",
+	DeviceTypeRobotVacuumS1:      "stop",
+	DeviceTypeRobotVacuumS1Plus:  "stop",
+	DeviceTypeRobotVacuumK10Plus: "stop",
+}
+
+// StopCommandFor returns the command that stops an in-progress operation on deviceType,
+// and false if the device type has none.
+func StopCommandFor(deviceType string) (string, bool) {
+	command, ok := stopCommands[deviceType]
+	return command, ok
+}
+
+// CancelCommand aborts an in-progress operation such as a curtain move or a vacuum cleaning run.
+// The SwitchBot API has no endpoint to cancel a command by commandId, so commandID is informational
+// only: CancelCommand looks up the device type and sends its stop command (see StopCommandFor).
+// Returns ErrCancelNotSupported if the device type has no stop command.
+func (c *Client) CancelCommand(ctx context.Context, deviceID, commandID string) error {
+	if deviceID == "" {
+		return fmt.Errorf("deviceID cannot be empty")
+	}
+	status, err := c.GetDeviceStatus(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	deviceType, _ := status["deviceType"].(string)
+	command, ok := StopCommandFor(deviceType)
+	if !ok {
+		return fmt.Errorf("%w: device %s is %q", ErrCancelNotSupported, deviceID, deviceType)
+	}
+	if _, err := c.SendDeviceCommand(ctx, deviceID, command, nil, ""); err != nil {
+		return fmt.Errorf("failed to cancel command %s on device %s: %w", commandID, deviceID, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCancelCommand(t *testing.T) {
+	testCases := []struct {
+		deviceType  string
+		wantCommand string
+	}{
+		{DeviceTypeCurtain, "pause"},
+		{DeviceTypeCurtain3, "pause"},
+		{DeviceTypeRobotVacuumS1, "stop"},
+		{DeviceTypeRobotVacuumK10Plus, "stop"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.deviceType, func(t *testing.T) {
+			var got []capturedCommand
+			capture := commandCaptureHandler(t, &got)
+			client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					statusHandler(fmt.Sprintf(`{"deviceId": "D1", "deviceType": %q}`, tc.deviceType))(w, r)
+					return
+				}
+				capture(w, r)
+			})
+
+			if err := client.CancelCommand(context.Background(), "D1", "CMD1"); err != nil {
+				t.Fatalf("CancelCommand() returned error: %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("sent %d commands; want 1", len(got))
+			}
+			if got[0].Path != "/v1.1/devices/D1/commands" || got[0].Command != tc.wantCommand ||
+				got[0].CommandType != "command" || got[0].Parameter != "default" {
+				t.Errorf("command = %+v; want %s with default parameter", got[0], tc.wantCommand)
+			}
+			if err := client.CheckParameter(tc.deviceType, tc.wantCommand, nil); err != nil {
+				t.Errorf("stop command is not in the schema registry: %v", err)
+			}
+		})
+	}
+
+	t.Run("Unsupported", func(t *testing.T) {
+		client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				t.Errorf("unexpected %s request", r.Method)
+			}
+			statusHandler(`{"deviceId": "B1", "deviceType": "Bot"}`)(w, r)
+		})
+		if err := client.CancelCommand(context.Background(), "B1", "CMD1"); !errors.Is(err, ErrCancelNotSupported) {
+			t.Errorf("CancelCommand() error = %v; want ErrCancelNotSupported", err)
+		}
+	})
+}
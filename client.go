@@ -3,31 +3,120 @@ package switchbot
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
-	DefaultBaseURL = "https://api.switch-bot.com"
-	apiVersion     = "v1.1"
+	DefaultBaseURL    = "https://api.switch-bot.com"
+	DefaultUserAgent  = "switchbot-go"
+	DefaultAPIVersion = "v1.1"
+
+	// DefaultMaxResponseBytes is the default limit on response body size (10MB).
+	DefaultMaxResponseBytes = 10 << 20
 )
 
+// knownErrorCodes are the documented SwitchBot status codes that always result in an *APIError,
+// even with WithStrictStatusCodes(false).
+var knownErrorCodes = map[int]bool{
+	151: true, // device type error
+	152: true, // device not found
+	160: true, // command not supported
+	161: true, // device offline
+	171: true, // hub offline
+	190: true, // internal error / invalid command format
+}
+
+// ErrDryRun is returned for requests skipped in dry-run mode (see WithDryRun).
+var ErrDryRun = errors.New("dry run: request not sent")
+
+// ErrResponseTooLarge is returned when a response body exceeds the limit set by WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// apiVersionPattern matches API version path segments such as "v1.0" and "v1.1".
+var apiVersionPattern = regexp.MustCompile(`^v\d+\.\d+$`)
+
+// signingHeaders are set by setAuthorizationHeader and cannot be overridden with WithDefaultHeader.
+var signingHeaders = []string{"Authorization", "T", "Sign", "Nonce"}
+
 type JSONMarshal func(v any) ([]byte, error)
 
 type JSONUnmarshal func(data []byte, v any) error
 
 // Client manages communication with the SwitchBot API.
+//
+// A Client is safe for concurrent use by multiple goroutines. Its configuration is fixed
+// once NewClient returns; any state recorded while serving requests is guarded by an
+// internal mutex. Create one Client and share it so the underlying http.Client can reuse
+// connections, rather than creating a Client per request.
 type Client struct {
-	token       string
-	secret      string
-	jsonEncoder JSONMarshal
-	jsonDecoder JSONUnmarshal
-	httpClient  *http.Client
-	baseURL     *url.URL
-	_           struct{}
+	token         string
+	secret        string
+	jsonEncoder   JSONMarshal
+	jsonDecoder   JSONUnmarshal
+	streamDecoder JSONStreamDecoder
+	httpClient    *http.Client
+	baseURL       *url.URL
+	apiVersion    string
+
+	strictStatusCodes    bool
+	additionalErrorCodes map[int]bool // Set by WithAdditionalErrorCodes
+	commandValidation    bool
+	rateLimitRetries     int
+	contentTypeOnGet     bool
+	useNumber            bool
+	emptyBodyPolicy      EmptyBodyPolicy
+	maxResponseBytes     int64
+	dryRun               bool
+	metrics              func(MetricEvent)
+	signingObserver      func(SigningInfo)
+	signer               func(secret, payload string) string
+	panicHandler         func(recovered any)
+	userAgent            string
+	logger               *slog.Logger
+	defaultHeaders       http.Header
+	sleep                Sleeper
+	credentialsProvider  CredentialsProvider
+	credentialsTTL       time.Duration
+	webhookBatchSize     int
+	build                *clientBuild // Set only while newClient applies options
+
+	requestInterceptors  []RequestInterceptor
+	responseInterceptors []ResponseInterceptor
+
+	// credMu guards the credentials cached from credentialsProvider and the in-flight fetch.
+	credMu            sync.Mutex
+	cachedToken       string
+	cachedSecret      string
+	cachedCredsExpiry time.Time
+	credFetch         *credentialsFetch
+
+	// mu guards the mutable state below, which is updated by doRequest.
+	// All fields above are read-only after NewClient returns.
+	mu             sync.Mutex
+	lastRateLimit  *RateLimitInfo
+	lastServerDate time.Time
+	lastLocalDate  time.Time
+
+	webhookCache       []WebhookDetails
+	webhookCacheExpiry time.Time
+	webhookCacheTTL    time.Duration
+	webhookCacheGen    uint64 // Incremented by invalidateWebhookCache
+
+	idempotentCommands map[string]*idempotentCommand // Keyed by SendDeviceCommandIdempotent key
+	idempotencyWindow  time.Duration
+	_                  struct{}
 }
 
 // ClientOption defines a function type for configuring the Client.
@@ -46,7 +135,45 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithInsecureSkipTLSVerify disables TLS certificate verification, e.g. to route traffic through
+// a debugging proxy with a self-signed certificate.
+//
+// WARNING: this makes the connection vulnerable to man-in-the-middle attacks, exposing the
+// token and request signatures. Use it only for local development and testing, never in production.
+//
+// The client's *http.Transport is cloned when the Client is built, so http.DefaultClient and any
+// client passed to WithHTTPClient are left untouched. NewClient fails if the transport is not an
+// *http.Transport.
+func WithInsecureSkipTLSVerify() ClientOption {
+	return func(c *Client) error {
+		c.build.insecureSkipVerify = true
+		return nil
+	}
+}
+
+// WithTunedTransport installs a dedicated *http.Transport keeping up to maxIdle idle connections,
+// at most maxIdlePerHost of them to one host (the standard library allows only 2 per host), so
+// concurrent batch operations reuse connections to api.switch-bot.com instead of sharing the
+// global pool of http.DefaultTransport. Other settings, such as proxy and timeouts, are those of
+// http.DefaultTransport.
+//
+// It replaces the transport of any client passed to WithHTTPClient, keeping the client's other
+// settings such as Timeout; that client itself is left untouched. Wrappers such as
+// WithInsecureSkipTLSVerify and WithRecorder apply to the tuned transport whatever the option order.
+func WithTunedTransport(maxIdle, maxIdlePerHost int) ClientOption {
+	return func(c *Client) error {
+		if maxIdle <= 0 || maxIdlePerHost <= 0 {
+			return fmt.Errorf("idle connection limits must be positive, got %d and %d", maxIdle, maxIdlePerHost)
+		}
+		c.build.maxIdle = maxIdle
+		c.build.maxIdlePerHost = maxIdlePerHost
+		return nil
+	}
+}
+
 // WithBaseURL sets a custom base URL for the SwitchBot Client.
+// A path on the base URL is kept as a prefix of every API path, e.g. requests through
+// "https://proxy.example.com/switchbot" go to "https://proxy.example.com/switchbot/v1.1/devices".
 func WithBaseURL(baseURL string) ClientOption {
 	return func(c *Client) error {
 		parsedURL, err := url.Parse(baseURL)
@@ -58,6 +185,85 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
+// WithAPIVersion sets the API version used in request paths, e.g. "v1.0" for legacy devices.
+// The version must match v<major>.<minor>. Defaults to DefaultAPIVersion.
+func WithAPIVersion(version string) ClientOption {
+	return func(c *Client) error {
+		if !apiVersionPattern.MatchString(version) {
+			return fmt.Errorf("invalid API version %q: must match v<major>.<minor>", version)
+		}
+		c.apiVersion = version
+		return nil
+	}
+}
+
+// WithDryRun enables dry-run mode for previewing automations. Requests other than GET are logged
+// (see WithLogger) instead of sent, and fail with an error wrapping ErrDryRun; Do additionally returns
+// a synthetic success Response. GET requests and POST-based reads (the webhook queryUrl and
+// queryDetails actions) still execute so real state can be inspected.
+func WithDryRun(enabled bool) ClientOption {
+	return func(c *Client) error {
+		c.dryRun = enabled
+		return nil
+	}
+}
+
+type readOnlyKey struct{}
+
+// withReadOnly marks requests made with ctx as reads that do not change state, so dry-run mode
+// sends them even when they use POST.
+func withReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// isReadOnly reports whether ctx was marked with withReadOnly.
+func isReadOnly(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey{}).(bool)
+	return readOnly
+}
+
+// RequestInterceptor inspects or mutates an outgoing request. Returning an error aborts the request.
+type RequestInterceptor func(*http.Request) error
+
+// ResponseInterceptor inspects a response before its body is read. Returning an error fails the request.
+type ResponseInterceptor func(*http.Response) error
+
+// WithRequestInterceptor adds an interceptor that runs on every request after all headers,
+// including the signing headers, are set. Interceptors run in the order they were added.
+func WithRequestInterceptor(interceptor RequestInterceptor) ClientOption {
+	return func(c *Client) error {
+		if interceptor == nil {
+			return fmt.Errorf("RequestInterceptor cannot be nil")
+		}
+		c.requestInterceptors = append(c.requestInterceptors, interceptor)
+		return nil
+	}
+}
+
+// WithResponseInterceptor adds an interceptor that runs on every response before its body is read.
+// Interceptors run in the order they were added.
+func WithResponseInterceptor(interceptor ResponseInterceptor) ClientOption {
+	return func(c *Client) error {
+		if interceptor == nil {
+			return fmt.Errorf("ResponseInterceptor cannot be nil")
+		}
+		c.responseInterceptors = append(c.responseInterceptors, interceptor)
+		return nil
+	}
+}
+
+// WithMaxResponseBytes limits how many bytes of a response body are read.
+// Larger responses fail with ErrResponseTooLarge. Defaults to DefaultMaxResponseBytes.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("max response bytes must be positive, got %d", n)
+		}
+		c.maxResponseBytes = n
+		return nil
+	}
+}
+
 // WithJSONEncoder sets a custom JSON handler for marshalling.
 func WithJSONEncoder(encoder JSONMarshal) ClientOption {
 	return func(c *Client) error {
@@ -80,125 +286,437 @@ func WithJSONDecoder(decoder JSONUnmarshal) ClientOption {
 	}
 }
 
+// WithCredentialsProvider sets a provider that is consulted for the token and secret
+// when signing requests, allowing credentials to be rotated without recreating the Client.
+// Results are cached for a short period (30s) to avoid calling the provider on every request.
+// When a provider is set, the token and secret passed to NewClient may be empty.
+func WithCredentialsProvider(provider CredentialsProvider) ClientOption {
+	return func(c *Client) error {
+		if provider == nil {
+			return fmt.Errorf("CredentialsProvider cannot be nil")
+		}
+		c.credentialsProvider = provider
+		return nil
+	}
+}
+
+// WithStrictStatusCodes controls how SwitchBot status codes other than 100 are handled.
+// When strict (the default), every non-100 code results in an *APIError. Pass false to restore
+// the legacy behavior of only failing on documented error codes (and any added with
+// WithAdditionalErrorCodes) and returning the response otherwise.
+func WithStrictStatusCodes(strict bool) ClientOption {
+	return func(c *Client) error {
+		c.strictStatusCodes = strict
+		return nil
+	}
+}
+
+// WithAdditionalErrorCodes treats the given SwitchBot status codes as errors (*APIError) in
+// addition to the documented ones (151, 152, 160, 161, 171 and 190). It only has an effect with
+// WithStrictStatusCodes(false): in strict mode, the default, every code other than 100 is already
+// an error. Code 100 means success and cannot be added. Calling it again adds to the set.
+func WithAdditionalErrorCodes(codes ...int) ClientOption {
+	return func(c *Client) error {
+		for _, code := range codes {
+			if code == 100 {
+				return fmt.Errorf("status code 100 is success and cannot be an error code")
+			}
+		}
+		if c.additionalErrorCodes == nil {
+			c.additionalErrorCodes = make(map[int]bool, len(codes))
+		}
+		for _, code := range codes {
+			c.additionalErrorCodes[code] = true
+		}
+		return nil
+	}
+}
+
+// WithContentTypeOnGet controls whether GET requests, which carry no body, are sent with
+// a Content-Type header. It is sent by default; pass false for proxies or API gateways that
+// reject bodyless requests declaring a content type.
+func WithContentTypeOnGet(enabled bool) ClientOption {
+	return func(c *Client) error {
+		c.contentTypeOnGet = enabled
+		return nil
+	}
+}
+
+// WithJSONNumbers decodes numbers in DeviceStatus and Device maps as json.Number instead of
+// float64, so large integers and precise readings keep their exact value and formatting.
+// Read them with DeviceStatus.Int and DeviceStatus.Float, which accept either representation.
+func WithJSONNumbers() ClientOption {
+	return func(c *Client) error {
+		c.useNumber = true
+		return nil
+	}
+}
+
+// WithLogger sets a structured logger. The client logs outgoing device commands at debug level.
+// By default nothing is logged.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) error {
+		if logger == nil {
+			return fmt.Errorf("Logger cannot be nil")
+		}
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request, e.g. to identify your application.
+func WithUserAgent(ua string) ClientOption {
+	return func(c *Client) error {
+		if ua == "" {
+			return fmt.Errorf("User-Agent cannot be empty")
+		}
+		c.userAgent = ua
+		return nil
+	}
+}
+
+// WithDefaultHeader adds a header sent with every request, such as a tracing header.
+// The signing headers (Authorization, t, sign, nonce) cannot be set this way.
+func WithDefaultHeader(key, value string) ClientOption {
+	return func(c *Client) error {
+		canonicalKey := http.CanonicalHeaderKey(key)
+		if slices.Contains(signingHeaders, canonicalKey) {
+			return fmt.Errorf("header %q is set by request signing and cannot be overridden", key)
+		}
+		c.defaultHeaders.Add(canonicalKey, value)
+		return nil
+	}
+}
+
+// WithSleeper sets the function used to wait between polls, mainly so tests can avoid real delays.
+func WithSleeper(sleeper Sleeper) ClientOption {
+	return func(c *Client) error {
+		if sleeper == nil {
+			return fmt.Errorf("Sleeper cannot be nil")
+		}
+		c.sleep = sleeper
+		return nil
+	}
+}
+
 // NewClient creates a new SwitchBot API client with optional configurations.
 func NewClient(token, secret string, options ...ClientOption) (*Client, error) {
-	if token == "" || secret == "" {
+	client, _, err := newClient(token, secret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.credentialsProvider == nil && (token == "" || secret == "") {
 		return nil, fmt.Errorf("token and secret must not be empty")
 	}
 
+	return client, nil
+}
+
+// clientBuild holds settings that only matter while the Client is being constructed.
+// Options record them in Client.build, and newClient returns them once options are applied.
+type clientBuild struct {
+	tokenEnv  string // Environment variables read by NewClientFromEnv
+	secretEnv string
+
+	// Transport settings, installed by buildTransport in a fixed order regardless of option order.
+	maxIdle            int       // WithTunedTransport; 0 keeps the configured transport
+	maxIdlePerHost     int       // WithTunedTransport
+	insecureSkipVerify bool      // WithInsecureSkipTLSVerify
+	recorder           io.Writer // WithRecorder
+	_                  struct{}
+}
+
+// newClient creates a client with the defaults and applies options, without checking credentials.
+// It also returns the construction-only settings recorded by the options.
+func newClient(token, secret string, options []ClientOption) (*Client, *clientBuild, error) {
 	baseURL, _ := url.Parse(DefaultBaseURL) // Error ignored as DefaultBaseURL is static
 
 	// Initialize client with defaults
 	client := &Client{
 		httpClient:  http.DefaultClient, // Default HTTP client
 		baseURL:     baseURL,
+		apiVersion:  DefaultAPIVersion,
 		token:       token,
 		secret:      secret,
 		jsonEncoder: json.Marshal,   // Default JSON encoder
 		jsonDecoder: json.Unmarshal, // Default JSON decoder
+
+		strictStatusCodes: true,
+		contentTypeOnGet:  true,
+		maxResponseBytes:  DefaultMaxResponseBytes,
+		userAgent:         DefaultUserAgent,
+		logger:            slog.New(slog.DiscardHandler),
+		defaultHeaders:    make(http.Header),
+		sleep:             sleepContext,
+		signer:            signHMACSHA256,
+		credentialsTTL:    defaultCredentialsTTL,
+		webhookBatchSize:  DefaultWebhookBatchSize,
+
+		webhookCacheTTL:   defaultWebhookCacheTTL,
+		idempotencyWindow: defaultIdempotencyWindow,
+
+		build: &clientBuild{tokenEnv: DefaultTokenEnv, secretEnv: DefaultSecretEnv},
 	}
 
 	// Apply all provided options
 	for _, option := range options {
 		if err := option(client); err != nil {
-			return nil, fmt.Errorf("failed to apply client option: %w", err)
+			return nil, nil, fmt.Errorf("failed to apply client option: %w", err)
 		}
 	}
 
-	return client, nil
+	build := client.build
+	client.build = nil
+	if err := client.buildTransport(build); err != nil {
+		return nil, nil, err
+	}
+	return client, build, nil
+}
+
+// buildTransport replaces the transport of the configured http.Client as recorded by the options:
+// the tuned transport, if any, is the base, TLS verification is disabled on a clone of the base,
+// and the recorder is installed outermost so it sees requests exactly as sent. The http.Client is
+// copied, never modified.
+func (c *Client) buildTransport(build *clientBuild) error {
+	if build.maxIdle == 0 && !build.insecureSkipVerify && build.recorder == nil {
+		return nil
+	}
+	transport := c.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if build.maxIdle > 0 {
+		tuned := http.DefaultTransport.(*http.Transport).Clone()
+		tuned.MaxIdleConns = build.maxIdle
+		tuned.MaxIdleConnsPerHost = build.maxIdlePerHost
+		transport = tuned
+	}
+	if build.insecureSkipVerify {
+		base, ok := transport.(*http.Transport)
+		if !ok {
+			return fmt.Errorf("cannot disable TLS verification on transport of type %T", transport)
+		}
+		base = base.Clone()
+		if base.TLSClientConfig == nil {
+			base.TLSClientConfig = &tls.Config{}
+		}
+		base.TLSClientConfig.InsecureSkipVerify = true
+		transport = base
+	}
+	if build.recorder != nil {
+		transport = &recordingTransport{next: transport, maxBytes: c.maxResponseBytes, w: build.recorder}
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+	return nil
 }
 
 // --- Generic Request Handling ---
 
+// setDefaultHeaders applies the User-Agent and any headers configured with WithDefaultHeader.
+// It runs before setAuthorizationHeader so the signing headers always take precedence.
+func (c *Client) setDefaultHeaders(req *http.Request) {
+	for key, values := range c.defaultHeaders {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+}
+
 // Response is the generic structure for SwitchBot API responses.
+//
+// An HTTP 2xx status alone does not mean success: the API reports application errors in
+// StatusCode with HTTP 200. By default any StatusCode other than 100 is returned as an *APIError,
+// but with WithStrictStatusCodes(false) an undocumented code is returned as a Response without
+// error, so check IsSuccess before trusting the body.
 type Response struct {
 	StatusCode int             `json:"statusCode"`
 	Message    string          `json:"message"`
 	Body       json.RawMessage `json:"body"` // Use json.RawMessage to delay parsing specific body structures
 }
 
+// IsSuccess reports whether the API accepted the request, i.e. StatusCode is 100.
+// A dry-run Response (see WithDryRun) also reports success, although nothing was sent.
+func (r *Response) IsSuccess() bool {
+	return r != nil && r.StatusCode == 100
+}
+
 // doRequest performs the actual HTTP request with authentication and error handling.
 func (c *Client) doRequest(ctx context.Context, method, path string, requestBody interface{}) (*Response, error) {
+	resp, _, err := c.doRequestTimed(ctx, method, path, requestBody)
+	return resp, err
+}
+
+// resolveURL resolves an API path against the base URL, prepending the base URL's path, if any.
+// Plain ResolveReference would replace that path because API paths are absolute.
+func (c *Client) resolveURL(rel *url.URL) *url.URL {
+	if prefix := strings.TrimSuffix(c.baseURL.Path, "/"); prefix != "" && strings.HasPrefix(rel.Path, "/") {
+		prefixed := *rel
+		prefixed.Path = prefix + rel.Path
+		prefixed.RawPath = ""
+		rel = &prefixed
+	}
+	return c.baseURL.ResolveReference(rel)
+}
+
+// doAttempt sends a single request and reports its HTTP round-trip duration,
+// measured from sending the request until the response body has been read.
+// The duration is zero if the request could not be sent.
+func (c *Client) doAttempt(ctx context.Context, method, path string, requestBody interface{}) (result *Response, took time.Duration, retErr error) {
 	relURL, err := url.Parse(path)
 	if err != nil {
-		return nil, fmt.Errorf("invalid path %q: %w", path, err)
+		return nil, 0, fmt.Errorf("invalid path %q: %w", path, err)
 	}
-	absURL := c.baseURL.ResolveReference(relURL)
+	absURL := c.resolveURL(relURL)
+	encoder, decoder := c.codecFor(ctx)
 
 	var bodyReader io.Reader
 	var reqBodyBytes []byte // Store request body bytes for potential logging or retries
 	if requestBody != nil {
-		reqBodyBytes, err = c.jsonEncoder(requestBody)
+		reqBodyBytes, err = encoder(requestBody)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, 0, fmt.Errorf("failed to marshal request body: %w", err)
 		}
 		bodyReader = bytes.NewReader(reqBodyBytes)
 	}
 
+	if c.dryRun && method != http.MethodGet && !isReadOnly(ctx) {
+		c.loggerFor(ctx).InfoContext(ctx, "dry run: request not sent", "method", method, "path", path, "body", string(reqBodyBytes))
+		dryRunResp := &Response{StatusCode: 100, Message: "dry run", Body: json.RawMessage(`{}`)}
+		return dryRunResp, 0, fmt.Errorf("%w: %s %s", ErrDryRun, method, path)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, absURL.String(), bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	c.setAuthorizationHeader(req)
+	c.setDefaultHeaders(req)
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		req.Header.Set(TraceIDHeader, traceID)
+	}
+	signing, err := c.setAuthorizationHeader(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, intercept := range c.requestInterceptors {
+		if err := intercept(req); err != nil {
+			return nil, 0, fmt.Errorf("request interceptor: %w", err)
+		}
+	}
 
+	var httpStatus int
+	if c.metrics != nil {
+		defer func() {
+			c.emitMetric(method, path, httpStatus, result, took, retErr)
+		}()
+	}
+
+	if c.signingObserver != nil {
+		c.signingObserver(signing)
+	}
+
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request to %s: %w", absURL.String(), err)
+		return nil, time.Since(start), fmt.Errorf("failed to execute request to %s: %w", absURL.String(), err)
 	}
+	httpStatus = resp.StatusCode
 	defer resp.Body.Close()
 
-	respBodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body from %s: %w", absURL.String(), err)
+	c.recordResponseMeta(resp.Header)
+
+	for _, intercept := range c.responseInterceptors {
+		if err := intercept(resp); err != nil {
+			return nil, time.Since(start), fmt.Errorf("response interceptor: %w", err)
+		}
 	}
 
 	// Attempt to parse into the standard SwitchBot response structure first
 	var apiResp Response
-	if err := c.jsonDecoder(respBodyBytes, &apiResp); err != nil {
+	var respBodyBytes []byte // The full body, or only its first bytes when streaming
+	var decodeErr error
+	var elapsed time.Duration
+	if streamDecoder := c.streamDecoderFor(ctx); streamDecoder != nil {
+		var tooLarge bool
+		respBodyBytes, tooLarge, decodeErr = streamDecode(resp.Body, streamDecoder, c.maxResponseBytes, &apiResp)
+		elapsed = time.Since(start)
+		if decodeErr != nil && ctx.Err() != nil {
+			// The body read was cut short; report the cancellation, not a decode error.
+			return nil, elapsed, fmt.Errorf("failed to read response body from %s: %w", absURL.String(), ctx.Err())
+		}
+		if tooLarge {
+			return nil, elapsed, fmt.Errorf("%w: response from %s exceeds %d bytes", ErrResponseTooLarge, absURL.String(), c.maxResponseBytes)
+		}
+	} else {
+		// Read one byte past the limit to detect oversized bodies.
+		respBodyBytes, err = io.ReadAll(io.LimitReader(resp.Body, c.maxResponseBytes+1))
+		elapsed = time.Since(start)
+		if err != nil {
+			if ctx.Err() != nil {
+				// Report the cancellation rather than the transport's error for the aborted read.
+				err = ctx.Err()
+			}
+			return nil, elapsed, fmt.Errorf("failed to read response body from %s: %w", absURL.String(), err)
+		}
+		if int64(len(respBodyBytes)) > c.maxResponseBytes {
+			return nil, elapsed, fmt.Errorf("%w: response from %s exceeds %d bytes", ErrResponseTooLarge, absURL.String(), c.maxResponseBytes)
+		}
+
+		// Trim trailing newlines and padding so custom decoders need not be lenient about them.
+		respBodyBytes = bytes.TrimSpace(respBodyBytes)
+		decodeErr = decoder(respBodyBytes, &apiResp)
+	}
+	if err := decodeErr; err != nil {
 		// If parsing fails, check HTTP status for error indication
 		if resp.StatusCode >= 400 {
-			return nil, &APIError{
-				StatusCode: resp.StatusCode, // Use HTTP status as primary code
+			return nil, elapsed, &APIError{
+				HTTPStatus: resp.StatusCode, // No API status code is available
+				RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
 				Message:    fmt.Sprintf("Received HTTP %d error with unparsable body", resp.StatusCode),
 				Body:       json.RawMessage(respBodyBytes), // Include raw body
 				Err:        err,                            // Include parsing error
 			}
 		}
-		// If HTTP status is OK (2xx/3xx) but body is not standard JSON, it's unusual
-		return nil, fmt.Errorf("failed to unmarshal successful response (HTTP %d) body: %w, body: %s", resp.StatusCode, err, string(respBodyBytes))
+		// If HTTP status is OK (2xx/3xx) but body is not standard JSON, it's unusual.
+		// Report it as an APIError too, so callers handle all API failures uniformly.
+		return nil, elapsed, &APIError{
+			HTTPStatus: resp.StatusCode, // No API status code is available
+			Message:    fmt.Sprintf("Received HTTP %d response with unparsable body", resp.StatusCode),
+			Body:       json.RawMessage(respBodyBytes[:min(len(respBodyBytes), maxBodySnippet)]), // Raw body, truncated
+			Err:        fmt.Errorf("failed to unmarshal successful response: %w", err),
+		}
 	}
 
 	// Check SwitchBot API specific status code for application-level errors
 	// StatusCode 100 is the primary success indicator from SwitchBot.
 	// Other codes (even with HTTP 200 OK) usually indicate specific issues.
 	if apiResp.StatusCode != 100 {
-		// Check if it's a known error code based on documentation
-		knownErrorCodes := map[int]bool{
-			151: true, // device type error
-			152: true, // device not found
-			160: true, // command not supported
-			161: true, // device offline
-			171: true, // hub offline
-			190: true, // internal error / invalid command format
-			// Add other known non-100 error codes if necessary
-		}
-		if knownErrorCodes[apiResp.StatusCode] {
-			return nil, &APIError{
+		// Check if it's a known error code based on documentation or WithAdditionalErrorCodes
+		if knownErrorCodes[apiResp.StatusCode] || c.additionalErrorCodes[apiResp.StatusCode] || c.strictStatusCodes {
+			return nil, elapsed, &APIError{
 				StatusCode: apiResp.StatusCode,
+				HTTPStatus: resp.StatusCode,
+				RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
 				Message:    apiResp.Message,
 				Body:       apiResp.Body,
 				Err:        fmt.Errorf("received API status code %d", apiResp.StatusCode),
 			}
 		}
-		// If it's not 100 and not a known error code, it might be unexpected or for async ops.
-		// Return the response but let caller be aware. Consider logging a warning.
-		// fmt.Printf("Warning: Received non-100 API status code %d: %s\n", apiResp.StatusCode, apiResp.Message)
+		// In lenient mode (WithStrictStatusCodes(false)), an unknown non-100 code is returned
+		// as a response and the caller is responsible for checking Response.StatusCode.
 	}
 	// Also check HTTP status code for client/server errors (redundant but safe)
 	if resp.StatusCode >= 400 {
+		// The envelope's statusCode (often 100) does not describe an HTTP failure, so no API code is reported.
 		errToReturn := &APIError{
-			StatusCode: resp.StatusCode, // Prioritize HTTP status code for 4xx/5xx
+			HTTPStatus: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
 			Message:    apiResp.Message, // Use message from parsed body if available
 			Body:       apiResp.Body,
 			Err:        fmt.Errorf("received HTTP status code %d", resp.StatusCode),
@@ -207,9 +725,9 @@ func (c *Client) doRequest(ctx context.Context, method, path string, requestBody
 		if errToReturn.Message == "" {
 			errToReturn.Message = fmt.Sprintf("Received HTTP %d error", resp.StatusCode)
 		}
-		return nil, errToReturn
+		return nil, elapsed, errToReturn
 	}
 
 	// If API status code is 100 and HTTP status is OK, return the successful response
-	return &apiResp, nil
+	return &apiResp, elapsed, nil
 }
@@ -1,15 +1,20 @@
 package switchbot
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -143,7 +148,7 @@ func TestNewClient(t *testing.T) {
 
 // setupMockServer creates a httptest server and a client pointing to it.
 // handlerFunc allows customizing the server's response for different tests.
-func setupMockServer(t *testing.T, handlerFunc http.HandlerFunc) (*Client, *httptest.Server) {
+func setupMockServer(t *testing.T, handlerFunc http.HandlerFunc, options ...ClientOption) (*Client, *httptest.Server) {
 	t.Helper() // Marks this as a test helper function
 
 	server := httptest.NewServer(handlerFunc)
@@ -153,7 +158,8 @@ func setupMockServer(t *testing.T, handlerFunc http.HandlerFunc) (*Client, *http
 	token := "mock-token"
 	secret := "mock-secret"
 
-	client, err := NewClient(token, secret, WithBaseURL(server.URL)) // Point client to mock server
+	// Point client to mock server; options are applied after WithBaseURL
+	client, err := NewClient(token, secret, append([]ClientOption{WithBaseURL(server.URL)}, options...)...)
 	if err != nil {
 		t.Fatalf("Failed to create client for mock server: %v", err)
 	}
@@ -161,6 +167,39 @@ func setupMockServer(t *testing.T, handlerFunc http.HandlerFunc) (*Client, *http
 	return client, server
 }
 
+// capturedCommand is a command request received by commandCaptureHandler.
+type capturedCommand struct {
+	Path        string
+	Command     string      `json:"command"`
+	CommandType string      `json:"commandType"`
+	Parameter   interface{} `json:"parameter"`
+}
+
+// commandCaptureHandler returns a handler that records each command request into got and replies with success.
+func commandCaptureHandler(t *testing.T, got *[]capturedCommand) http.HandlerFunc {
+	t.Helper()
+	var mu sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		cmd := capturedCommand{Path: r.URL.Path}
+		if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+			t.Errorf("Failed to decode command request: %v", err)
+		}
+		mu.Lock()
+		*got = append(*got, cmd)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 100, "message": "success", "body": {}}`)
+	}
+}
+
+// statusHandler returns a handler that replies with a successful API response wrapping body.
+func statusHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"statusCode": 100, "message": "success", "body": %s}`, body)
+	}
+}
+
 func TestDoRequest_Success(t *testing.T) {
 	// Mocked response body content for GetDevices
 	mockDevicesBody := GetDevicesResponse{
@@ -257,9 +296,15 @@ func TestDoRequest_APIError(t *testing.T) {
 	if apiErr.StatusCode != errorCode {
 		t.Errorf("APIError StatusCode = %d; want %d", apiErr.StatusCode, errorCode)
 	}
+	if apiErr.HTTPStatus != http.StatusOK {
+		t.Errorf("APIError HTTPStatus = %d; want %d", apiErr.HTTPStatus, http.StatusOK)
+	}
 	if apiErr.Message != errorMessage {
 		t.Errorf("APIError Message = %q; want %q", apiErr.Message, errorMessage)
 	}
+	if !errors.Is(err, ErrDeviceOffline) {
+		t.Errorf("errors.Is(err, ErrDeviceOffline) = false for %v", err)
+	}
 }
 
 func TestDoRequest_HTTPError(t *testing.T) {
@@ -286,9 +331,15 @@ func TestDoRequest_HTTPError(t *testing.T) {
 		t.Fatalf("Expected error of type *APIError, got %T: %v", err, err)
 	}
 
-	// For HTTP errors, we prioritize the HTTP status code
-	if apiErr.StatusCode != httpStatusCode {
-		t.Errorf("APIError StatusCode = %d; want %d (HTTP Status)", apiErr.StatusCode, httpStatusCode)
+	// For HTTP errors, we prioritize the HTTP status code; the envelope's statusCode is not reported
+	if apiErr.HTTPStatus != httpStatusCode {
+		t.Errorf("APIError HTTPStatus = %d; want %d (HTTP Status)", apiErr.HTTPStatus, httpStatusCode)
+	}
+	if apiErr.StatusCode != 0 {
+		t.Errorf("APIError StatusCode = %d; want 0 (no API status code for HTTP errors)", apiErr.StatusCode)
+	}
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("errors.Is(err, ErrUnauthorized) = false for %v", err)
 	}
 	// Message might come from the parsed body or be generated
 	if apiErr.Message != errorMessage {
@@ -301,6 +352,82 @@ func TestDoRequest_HTTPError(t *testing.T) {
 	}
 }
 
+func TestDoRequest_InvalidJSONOnSuccess(t *testing.T) {
+	body := "<html>" + strings.Repeat("x", 1000) + "</html>"
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, body)
+	}
+	client, _ := setupMockServer(t, handler)
+
+	_, err := client.GetDevices(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected error of type *APIError, got %T: %v", err, err)
+	}
+	if apiErr.HTTPStatus != http.StatusOK {
+		t.Errorf("APIError HTTPStatus = %d; want 200", apiErr.HTTPStatus)
+	}
+	if apiErr.StatusCode != 0 {
+		t.Errorf("APIError StatusCode = %d; want 0", apiErr.StatusCode)
+	}
+	if len(apiErr.Body) != maxBodySnippet || !strings.HasPrefix(body, string(apiErr.Body)) {
+		t.Errorf("APIError Body = %q; want the first %d bytes of the body", apiErr.Body, maxBodySnippet)
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("errors.As(err, *json.SyntaxError) = false for %v", err)
+	}
+}
+
+func TestDoRequest_ContextCanceledDuringBodyRead(t *testing.T) {
+	// slowHandler sends the headers and part of the body, then stalls until the client goes away.
+	slowHandler := func(started chan<- struct{}) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"statusCode": 100, "message": "success", "body": {"deviceList": [`)
+			w.(http.Flusher).Flush()
+			close(started)
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}
+
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Streaming=%v", streaming), func(t *testing.T) {
+			started := make(chan struct{})
+			_, server := setupMockServer(t, slowHandler(started))
+			options := []ClientOption{WithBaseURL(server.URL)}
+			if streaming {
+				options = append(options, WithStreamingDecoder(func(r io.Reader, v any) error { return json.NewDecoder(r).Decode(v) }))
+			}
+			client, err := NewClient("token", "secret", options...)
+			if err != nil {
+				t.Fatalf("NewClient() returned error: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				<-started
+				cancel()
+			}()
+			resp, err := client.GetDevices(ctx)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("GetDevices() error = %v; want context.Canceled", err)
+			}
+			if resp != nil {
+				t.Errorf("GetDevices() returned a partial response: %+v", resp)
+			}
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				t.Errorf("GetDevices() error is an APIError: %v", err)
+			}
+		})
+	}
+}
+
 func TestDoRequest_NetworkError(t *testing.T) {
 	// Create a server that immediately closes, simulating a network error
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -373,15 +500,20 @@ func TestDoRequest_CustomJSONHandler(t *testing.T) {
 		fmt.Fprintln(w, mockResponse)
 	}
 
-	client, _ := setupMockServer(t, handler) // Gets a client pointed to the mock server
+	_, server := setupMockServer(t, handler)
 
-	// Apply custom handlers AFTER creating the client for this test
-	// (Alternatively, create a new client with options)
-	client.jsonEncoder = customEncoder
-	client.jsonDecoder = customDecoder
+	// Configure the handlers through options: a Client must not be mutated after construction
+	client, err := NewClient("mock-token", "mock-secret",
+		WithBaseURL(server.URL),
+		WithJSONEncoder(customEncoder),
+		WithJSONDecoder(customDecoder),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client with custom JSON handlers: %v", err)
+	}
 
 	// Test with GET (only decoder should be called)
-	_, err := client.GetDevices(context.Background()) // GetDevices uses GET
+	_, err = client.GetDevices(context.Background()) // GetDevices uses GET
 	if err != nil {
 		t.Fatalf("GetDevices with custom handlers returned error: %v", err)
 	}
@@ -408,3 +540,552 @@ func TestDoRequest_CustomJSONHandler(t *testing.T) {
 		t.Error("Custom JSON decoder was not called for POST request")
 	}
 }
+
+func TestClient_ConcurrentUse(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "100")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 100, "message": "success", "body": {"deviceList": [{"deviceId": "D1"}], "infraredRemoteList": []}}`)
+	}
+
+	client, _ := setupMockServer(t, handler)
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.GetDevices(context.Background())
+			if err != nil {
+				errs <- err
+				return
+			}
+			if len(resp.DeviceList) != 1 {
+				errs <- fmt.Errorf("got %d devices; want 1", len(resp.DeviceList))
+				return
+			}
+			_ = client.LastRateLimit()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent GetDevices() failed: %v", err)
+	}
+}
+
+func TestClient_Headers(t *testing.T) {
+	t.Run("DefaultUserAgent", func(t *testing.T) {
+		var gotUA string
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			gotUA = r.Header.Get("User-Agent")
+			statusHandler(`{}`)(w, r)
+		}
+		client, _ := setupMockServer(t, handler)
+		if _, err := client.GetDeviceStatus(context.Background(), "D1"); err != nil {
+			t.Fatalf("GetDeviceStatus() returned error: %v", err)
+		}
+		if gotUA != DefaultUserAgent {
+			t.Errorf("User-Agent = %q; want %q", gotUA, DefaultUserAgent)
+		}
+	})
+
+	t.Run("CustomHeaders", func(t *testing.T) {
+		var got http.Header
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Clone()
+			statusHandler(`{}`)(w, r)
+		}
+		_, server := setupMockServer(t, handler)
+		client, err := NewClient("mock-token", "mock-secret",
+			WithBaseURL(server.URL),
+			WithUserAgent("my-app/1.2"),
+			WithDefaultHeader("X-Trace-Id", "abc123"),
+		)
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+		if _, err := client.GetDeviceStatus(context.Background(), "D1"); err != nil {
+			t.Fatalf("GetDeviceStatus() returned error: %v", err)
+		}
+		if ua := got.Get("User-Agent"); ua != "my-app/1.2" {
+			t.Errorf("User-Agent = %q; want %q", ua, "my-app/1.2")
+		}
+		if trace := got.Get("X-Trace-Id"); trace != "abc123" {
+			t.Errorf("X-Trace-Id = %q; want %q", trace, "abc123")
+		}
+		if auth := got.Get("Authorization"); auth != "mock-token" {
+			t.Errorf("Authorization = %q; want %q", auth, "mock-token")
+		}
+	})
+
+	t.Run("SigningHeadersProtected", func(t *testing.T) {
+		for _, key := range []string{"Authorization", "t", "sign", "NONCE"} {
+			if _, err := NewClient("token", "secret", WithDefaultHeader(key, "x")); err == nil {
+				t.Errorf("WithDefaultHeader(%q) did not return an error", key)
+			}
+		}
+	})
+}
+
+func TestDoRequest_UnknownStatusCode(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 181, "message": "unexpected", "body": {}}`)
+	}
+
+	t.Run("StrictByDefault", func(t *testing.T) {
+		client, _ := setupMockServer(t, handler)
+
+		_, err := client.GetDeviceStatus(context.Background(), "D1")
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("GetDeviceStatus() error = %v; want *APIError", err)
+		}
+		if apiErr.StatusCode != 181 || apiErr.HTTPStatus != http.StatusOK {
+			t.Errorf("APIError StatusCode, HTTPStatus = %d, %d; want 181, 200", apiErr.StatusCode, apiErr.HTTPStatus)
+		}
+	})
+
+	t.Run("Lenient", func(t *testing.T) {
+		_, server := setupMockServer(t, handler)
+		client, err := NewClient("mock-token", "mock-secret", WithBaseURL(server.URL), WithStrictStatusCodes(false))
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+
+		resp, err := client.doRequest(context.Background(), http.MethodGet, "/v1.1/devices/D1/status", nil)
+		if err != nil {
+			t.Fatalf("doRequest() in lenient mode returned error: %v", err)
+		}
+		if resp.StatusCode != 181 {
+			t.Errorf("Response StatusCode = %d; want 181", resp.StatusCode)
+		}
+		if resp.IsSuccess() {
+			t.Error("IsSuccess() = true for status code 181; want false")
+		}
+	})
+
+	t.Run("AdditionalErrorCode", func(t *testing.T) {
+		_, server := setupMockServer(t, handler)
+		client, err := NewClient("mock-token", "mock-secret", WithBaseURL(server.URL),
+			WithStrictStatusCodes(false), WithAdditionalErrorCodes(181))
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+
+		_, err = client.doRequest(context.Background(), http.MethodGet, "/v1.1/devices/D1/status", nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != 181 {
+			t.Errorf("doRequest() error = %v; want *APIError with status code 181", err)
+		}
+
+		if _, err := NewClient("mock-token", "mock-secret", WithAdditionalErrorCodes(100)); err == nil {
+			t.Error("WithAdditionalErrorCodes(100) did not return an error")
+		}
+	})
+}
+
+func TestResponse_IsSuccess(t *testing.T) {
+	testCases := []struct {
+		name string
+		resp *Response
+		want bool
+	}{
+		{"Success", &Response{StatusCode: 100}, true},
+		{"DeviceOffline", &Response{StatusCode: 161}, false},
+		{"Unknown", &Response{StatusCode: 181}, false},
+		{"Zero", &Response{}, false},
+		{"Nil", nil, false},
+	}
+	for _, tc := range testCases {
+		if got := tc.resp.IsSuccess(); got != tc.want {
+			t.Errorf("%s: IsSuccess() = %v; want %v", tc.name, got, tc.want)
+		}
+	}
+
+	client, _ := setupMockServer(t, statusHandler(`{}`))
+	resp, err := client.Do(context.Background(), http.MethodGet, "/v1.1/devices", nil)
+	if err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	if !resp.IsSuccess() {
+		t.Errorf("IsSuccess() = false for %+v; want true", resp)
+	}
+}
+
+func TestClient_APIVersion(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		var gotPath string
+		client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			statusHandler(`{"deviceList": [], "infraredRemoteList": []}`)(w, r)
+		})
+		if _, err := client.GetDevices(context.Background()); err != nil {
+			t.Fatalf("GetDevices() returned error: %v", err)
+		}
+		if gotPath != "/v1.1/devices" {
+			t.Errorf("path = %q; want /v1.1/devices", gotPath)
+		}
+	})
+
+	t.Run("Pinned", func(t *testing.T) {
+		var gotPaths []string
+		client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			gotPaths = append(gotPaths, r.URL.Path)
+			statusHandler(`{}`)(w, r)
+		}, WithAPIVersion("v1.0"))
+		if _, err := client.GetDeviceStatus(context.Background(), "D1"); err != nil {
+			t.Fatalf("GetDeviceStatus() returned error: %v", err)
+		}
+		if _, err := client.SendDeviceCommand(context.Background(), "D1", "turnOn", nil, ""); err != nil {
+			t.Fatalf("SendDeviceCommand() returned error: %v", err)
+		}
+		for _, path := range gotPaths {
+			if !strings.HasPrefix(path, "/v1.0/") {
+				t.Errorf("path = %q; want /v1.0/ prefix", path)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, version := range []string{"", "1.1", "v1", "v1.1/devices", "V1.1"} {
+			if _, err := NewClient("token", "secret", WithAPIVersion(version)); err == nil {
+				t.Errorf("NewClient(WithAPIVersion(%q)) returned nil error", version)
+			}
+		}
+	})
+}
+
+func TestWithBaseURL_PathPrefix(t *testing.T) {
+	var gotPaths []string
+	_, server := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+		statusHandler(`{"deviceList": [], "infraredRemoteList": []}`)(w, r)
+	})
+
+	for _, baseURL := range []string{server.URL + "/switchbot", server.URL + "/switchbot/"} {
+		gotPaths = nil
+		client, err := NewClient("token", "secret", WithBaseURL(baseURL))
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+		if _, err := client.GetDevices(context.Background()); err != nil {
+			t.Fatalf("GetDevices() returned error: %v", err)
+		}
+		if len(gotPaths) != 1 || gotPaths[0] != "/switchbot/v1.1/devices" {
+			t.Errorf("WithBaseURL(%q): paths = %v; want [/switchbot/v1.1/devices]", baseURL, gotPaths)
+		}
+	}
+
+	client, _ := NewClient("token", "secret", WithBaseURL("https://proxy.example.com/switchbot"))
+	rel, _ := url.Parse("/v1.1/devices?limit=1")
+	if got := client.resolveURL(rel).String(); got != "https://proxy.example.com/switchbot/v1.1/devices?limit=1" {
+		t.Errorf("resolveURL() = %q; want the prefix and query kept", got)
+	}
+}
+
+func TestDoRequest_MaxResponseBytes(t *testing.T) {
+	body := `{"deviceList": [], "infraredRemoteList": []}`
+
+	t.Run("Exceeded", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(body), WithMaxResponseBytes(2))
+		if _, err := client.GetDevices(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("GetDevices() error = %v; want ErrResponseTooLarge", err)
+		}
+	})
+
+	t.Run("WithinLimit", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(body), WithMaxResponseBytes(1024))
+		if _, err := client.GetDevices(context.Background()); err != nil {
+			t.Errorf("GetDevices() returned error: %v", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := NewClient("token", "secret", WithMaxResponseBytes(0)); err == nil {
+			t.Error("NewClient(WithMaxResponseBytes(0)) returned nil error")
+		}
+	})
+}
+
+func TestDoRequest_TrailingWhitespace(t *testing.T) {
+	// strictDecoder rejects any input that does not start and end with a JSON delimiter.
+	strictDecoder := func(data []byte, v any) error {
+		if len(data) == 0 || (data[0] != '{' && data[0] != '[') || (data[len(data)-1] != '}' && data[len(data)-1] != ']') {
+			return fmt.Errorf("strict decoder: unexpected padding in %q", data)
+		}
+		return json.Unmarshal(data, v)
+	}
+
+	testCases := []struct {
+		name     string
+		response string
+	}{
+		{"TrailingNewline", "{\"statusCode\": 100, \"message\": \"success\", \"body\": {\"deviceId\": \"D1\"}}\n"},
+		{"PaddedCRLF", " \t{\"statusCode\": 100, \"message\": \"success\", \"body\": {\"deviceId\": \"D1\"}}\r\n\r\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, server := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				io.WriteString(w, tc.response)
+			})
+			client, err := NewClient("token", "secret", WithBaseURL(server.URL), WithJSONDecoder(strictDecoder))
+			if err != nil {
+				t.Fatalf("NewClient() returned error: %v", err)
+			}
+			status, err := client.GetDeviceStatus(context.Background(), "D1")
+			if err != nil {
+				t.Fatalf("GetDeviceStatus() returned error: %v", err)
+			}
+			if status["deviceId"] != "D1" {
+				t.Errorf("deviceId = %v; want D1", status["deviceId"])
+			}
+		})
+	}
+
+	t.Run("PaddedEmptyBody", func(t *testing.T) {
+		// paddingDecoder leaves whitespace around the raw body, as a lenient custom decoder may.
+		paddingDecoder := func(data []byte, v any) error {
+			if err := json.Unmarshal(data, v); err != nil {
+				return err
+			}
+			if resp, ok := v.(*Response); ok {
+				resp.Body = append(append([]byte(" \n"), resp.Body...), "\t\r\n"...)
+			}
+			return nil
+		}
+		client, _ := setupMockServer(t, statusHandler(`null`), WithJSONDecoder(paddingDecoder))
+		status, err := client.GetDeviceStatus(context.Background(), "D1")
+		if err != nil {
+			t.Fatalf("GetDeviceStatus() returned error: %v", err)
+		}
+		if status == nil || len(status) != 0 {
+			t.Errorf("GetDeviceStatus() = %v; want empty map", status)
+		}
+	})
+}
+
+func TestDoRequest_DryRun(t *testing.T) {
+	var methods []string
+	_, server := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		statusHandler(`{"deviceId": "L1", "lockState": "locked"}`)(w, r)
+	})
+	var logs bytes.Buffer
+	client, err := NewClient("token", "secret", WithBaseURL(server.URL), WithDryRun(true),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	if _, err := client.SendDeviceCommand(context.Background(), "L1", "unlock", nil, ""); !errors.Is(err, ErrDryRun) {
+		t.Errorf("SendDeviceCommand() error = %v; want ErrDryRun", err)
+	}
+	if !strings.Contains(logs.String(), "dry run") || !strings.Contains(logs.String(), "unlock") {
+		t.Errorf("log output = %q; want dry-run entry with the command", logs.String())
+	}
+
+	resp, err := client.Do(context.Background(), http.MethodPost, "/v1.1/scenes/S1/execute", nil)
+	if !errors.Is(err, ErrDryRun) {
+		t.Errorf("Do() error = %v; want ErrDryRun", err)
+	}
+	if resp == nil || resp.StatusCode != 100 {
+		t.Errorf("Do() response = %+v; want synthetic success", resp)
+	}
+
+	status, err := client.GetDeviceStatus(context.Background(), "L1")
+	if err != nil {
+		t.Fatalf("GetDeviceStatus() returned error: %v", err)
+	}
+	if status["lockState"] != "locked" {
+		t.Errorf("lockState = %v; want locked", status["lockState"])
+	}
+	if len(methods) != 1 || methods[0] != http.MethodGet {
+		t.Errorf("server received %v; want only the GET", methods)
+	}
+}
+
+func TestClient_Interceptors(t *testing.T) {
+	t.Run("MutateAndObserve", func(t *testing.T) {
+		var gotHeader string
+		_, server := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			gotHeader = r.Header.Get("X-Instrumented")
+			w.Header().Set("X-Upstream", "yes")
+			statusHandler(`{}`)(w, r)
+		})
+
+		var sawSign bool
+		var order []string
+		var upstream string
+		client, err := NewClient("token", "secret", WithBaseURL(server.URL),
+			WithRequestInterceptor(func(r *http.Request) error {
+				sawSign = r.Header.Get("Sign") != "" && r.Header.Get("Authorization") != ""
+				r.Header.Set("X-Instrumented", "1")
+				order = append(order, "first")
+				return nil
+			}),
+			WithRequestInterceptor(func(r *http.Request) error {
+				order = append(order, "second")
+				return nil
+			}),
+			WithResponseInterceptor(func(resp *http.Response) error {
+				upstream = resp.Header.Get("X-Upstream")
+				return nil
+			}),
+		)
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+
+		if _, err := client.GetDeviceStatus(context.Background(), "D1"); err != nil {
+			t.Fatalf("GetDeviceStatus() returned error: %v", err)
+		}
+		if !sawSign {
+			t.Error("request interceptor ran before the signing headers were set")
+		}
+		if gotHeader != "1" {
+			t.Errorf("X-Instrumented = %q; want 1", gotHeader)
+		}
+		if strings.Join(order, ",") != "first,second" {
+			t.Errorf("interceptor order = %v; want first,second", order)
+		}
+		if upstream != "yes" {
+			t.Errorf("response interceptor saw X-Upstream = %q; want yes", upstream)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var requests int
+		_, server := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			statusHandler(`{}`)(w, r)
+		})
+		errBlocked := errors.New("blocked")
+
+		client, _ := NewClient("token", "secret", WithBaseURL(server.URL),
+			WithRequestInterceptor(func(*http.Request) error { return errBlocked }))
+		if _, err := client.GetDeviceStatus(context.Background(), "D1"); !errors.Is(err, errBlocked) {
+			t.Errorf("GetDeviceStatus() error = %v; want request interceptor error", err)
+		}
+		if requests != 0 {
+			t.Errorf("server received %d requests; want 0", requests)
+		}
+
+		client, _ = NewClient("token", "secret", WithBaseURL(server.URL),
+			WithResponseInterceptor(func(*http.Response) error { return errBlocked }))
+		if _, err := client.GetDeviceStatus(context.Background(), "D1"); !errors.Is(err, errBlocked) {
+			t.Errorf("GetDeviceStatus() error = %v; want response interceptor error", err)
+		}
+
+		if _, err := NewClient("token", "secret", WithRequestInterceptor(nil)); err == nil {
+			t.Error("NewClient(WithRequestInterceptor(nil)) returned nil error")
+		}
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestWithTunedTransport(t *testing.T) {
+	custom := &http.Client{Timeout: 5 * time.Second}
+	client, err := NewClient("token", "secret", WithHTTPClient(custom), WithTunedTransport(50, 20))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok || transport == http.DefaultTransport {
+		t.Fatalf("transport = %T; want a dedicated *http.Transport", client.httpClient.Transport)
+	}
+	if transport.MaxIdleConns != 50 || transport.MaxIdleConnsPerHost != 20 {
+		t.Errorf("MaxIdleConns, MaxIdleConnsPerHost = %d, %d; want 50, 20", transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+	}
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v; want the WithHTTPClient value kept", client.httpClient.Timeout)
+	}
+	if custom.Transport != nil {
+		t.Error("the client passed to WithHTTPClient was modified")
+	}
+
+	if _, err := NewClient("token", "secret", WithTunedTransport(0, 10)); err == nil {
+		t.Error("WithTunedTransport(0, 10) did not return an error")
+	}
+
+	// Options applied before WithTunedTransport still wrap the tuned transport.
+	client, err = NewClient("token", "secret",
+		WithRecorder(io.Discard), WithInsecureSkipTLSVerify(), WithTunedTransport(50, 20), WithHTTPClient(custom))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	recorder, ok := client.httpClient.Transport.(*recordingTransport)
+	if !ok {
+		t.Fatalf("transport = %T; want the recorder outermost", client.httpClient.Transport)
+	}
+	transport, ok = recorder.next.(*http.Transport)
+	if !ok || transport.MaxIdleConns != 50 || !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("recorded transport = %T; want the tuned transport with TLS verification disabled", recorder.next)
+	}
+}
+
+func TestWithInsecureSkipTLSVerify(t *testing.T) {
+	server := httptest.NewUnstartedServer(statusHandler(`{"deviceList": []}`))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // Silence the expected handshake failure
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	t.Run("VerifiesByDefault", func(t *testing.T) {
+		client, err := NewClient("token", "secret", WithBaseURL(server.URL))
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+		if _, err := client.GetDevices(context.Background()); err == nil {
+			t.Error("GetDevices() succeeded against a self-signed certificate without WithInsecureSkipTLSVerify")
+		}
+	})
+
+	t.Run("SkipsVerification", func(t *testing.T) {
+		client, err := NewClient("token", "secret", WithBaseURL(server.URL), WithInsecureSkipTLSVerify())
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+		if _, err := client.GetDevices(context.Background()); err != nil {
+			t.Fatalf("GetDevices() returned error: %v", err)
+		}
+		if client.httpClient == http.DefaultClient {
+			t.Error("http.DefaultClient was reused instead of cloned")
+		}
+		if cfg := http.DefaultTransport.(*http.Transport).TLSClientConfig; cfg != nil && cfg.InsecureSkipVerify {
+			t.Error("http.DefaultTransport was modified")
+		}
+	})
+
+	t.Run("CustomClientUntouched", func(t *testing.T) {
+		custom := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{}}
+		client, err := NewClient("token", "secret", WithHTTPClient(custom), WithInsecureSkipTLSVerify())
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+		if cfg := custom.Transport.(*http.Transport).TLSClientConfig; cfg != nil && cfg.InsecureSkipVerify {
+			t.Error("the transport passed to WithHTTPClient was modified")
+		}
+		if !client.httpClient.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
+			t.Error("the cloned transport still verifies certificates")
+		}
+		if client.httpClient.Timeout != 5*time.Second {
+			t.Errorf("cloned client Timeout = %v; want 5s", client.httpClient.Timeout)
+		}
+	})
+
+	t.Run("UnsupportedTransport", func(t *testing.T) {
+		custom := &http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, nil })}
+		if _, err := NewClient("token", "secret", WithHTTPClient(custom), WithInsecureSkipTLSVerify()); err == nil {
+			t.Error("WithInsecureSkipTLSVerify() accepted a non-*http.Transport transport")
+		}
+	})
+}
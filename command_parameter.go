@@ -0,0 +1,38 @@
+package switchbot
+
+import "encoding/json"
+
+// CommandParameter is a typed command parameter for SendDeviceCommandWithType, documenting the
+// shapes the API accepts: DefaultParameter for commands without one, StringParameter for
+// formatted values such as "26,1,3,on" or "50", and ObjectParameter for JSON objects.
+// SendDeviceCommand and CheckParameter also accept these types in place of plain values.
+type CommandParameter interface {
+	// parameterValue returns the plain value sent as the JSON parameter.
+	parameterValue() interface{}
+}
+
+// DefaultParameter is the "default" parameter of commands that take none, e.g. turnOn.
+type DefaultParameter struct{}
+
+// StringParameter is a parameter sent as a JSON string, e.g. "50" for setBrightness.
+type StringParameter string
+
+// ObjectParameter is a parameter sent as a JSON object, e.g. for Keypad createKey.
+type ObjectParameter map[string]interface{}
+
+func (DefaultParameter) parameterValue() interface{}  { return "default" }
+func (p StringParameter) parameterValue() interface{} { return string(p) }
+func (p ObjectParameter) parameterValue() interface{} { return map[string]interface{}(p) }
+
+// MarshalJSON encodes the parameter as "default".
+func (p DefaultParameter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.parameterValue())
+}
+
+// plainParameter returns the plain value of a CommandParameter, and any other value unchanged.
+func plainParameter(parameter interface{}) interface{} {
+	if p, ok := parameter.(CommandParameter); ok {
+		return p.parameterValue()
+	}
+	return parameter
+}
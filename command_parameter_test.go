@@ -0,0 +1,77 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSendDeviceCommandWithType_Parameter(t *testing.T) {
+	tests := []struct {
+		name      string
+		parameter CommandParameter
+		want      interface{}
+	}{
+		{"Nil", nil, "default"},
+		{"Default", DefaultParameter{}, "default"},
+		{"String", StringParameter("26,1,3,on"), "26,1,3,on"},
+		{"Object", ObjectParameter{"name": "guest", "type": "permanent"}, map[string]interface{}{"name": "guest", "type": "permanent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []capturedCommand
+			client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+			if _, err := client.SendDeviceCommandWithType(context.Background(), "D1", "turnOn", tt.parameter, CommandTypeStandard); err != nil {
+				t.Fatalf("SendDeviceCommandWithType() returned error: %v", err)
+			}
+			if len(got) != 1 || !reflect.DeepEqual(got[0].Parameter, tt.want) {
+				t.Errorf("commands = %+v; want parameter %v", got, tt.want)
+			}
+
+			// The same value passed through the interface{} parameter is sent identically.
+			got = nil
+			if _, err := client.SendDeviceCommand(context.Background(), "D1", "turnOn", tt.parameter, ""); err != nil {
+				t.Fatalf("SendDeviceCommand() returned error: %v", err)
+			}
+			if len(got) != 1 || !reflect.DeepEqual(got[0].Parameter, tt.want) {
+				t.Errorf("commands = %+v; want parameter %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandParameterJSON(t *testing.T) {
+	tests := []struct {
+		parameter CommandParameter
+		want      string
+	}{
+		{DefaultParameter{}, `"default"`},
+		{StringParameter("50"), `"50"`},
+		{ObjectParameter{"a": 1}, `{"a":1}`},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.parameter)
+		if err != nil || string(b) != tt.want {
+			t.Errorf("json.Marshal(%#v) = %s, %v; want %s", tt.parameter, b, err, tt.want)
+		}
+	}
+}
+
+func TestCheckParameter_CommandParameter(t *testing.T) {
+	client, err := NewClient("token", "secret")
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	if err := client.CheckParameter(DeviceTypeColorBulb, "setBrightness", StringParameter("50")); err != nil {
+		t.Errorf("CheckParameter(StringParameter(50)) returned error: %v", err)
+	}
+	if err := client.CheckParameter(DeviceTypeColorBulb, "turnOn", DefaultParameter{}); err != nil {
+		t.Errorf("CheckParameter(DefaultParameter{}) returned error: %v", err)
+	}
+	if err := client.CheckParameter(DeviceTypeColorBulb, "setBrightness", StringParameter("150")); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("CheckParameter(StringParameter(150)) error = %v; want ErrInvalidParameter", err)
+	}
+}
@@ -0,0 +1,402 @@
+package switchbot
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnknownCommand is returned by CheckParameter when no schema is registered for the device type and command.
+	ErrUnknownCommand = errors.New("unknown command for device type")
+	// ErrInvalidParameter is returned by CheckParameter when the parameter does not match the command's schema.
+	ErrInvalidParameter = errors.New("invalid command parameter")
+)
+
+// ParameterSchema describes the parameter accepted by a single command.
+type ParameterSchema struct {
+	Description string // Human-readable format, e.g. "1-100"
+	validate    func(parameter interface{}) error
+	_           struct{}
+}
+
+// Validate checks parameter, a plain value or a CommandParameter, against the schema.
+func (s ParameterSchema) Validate(parameter interface{}) error {
+	if s.validate == nil {
+		return nil
+	}
+	return s.validate(plainParameter(parameter))
+}
+
+// --- Schemas ---
+
+var (
+	defaultParameterSchema  = ParameterSchema{Description: "default", validate: validateDefaultParameter}
+	brightnessSchema        = ParameterSchema{Description: "1-100", validate: validateIntRange(1, 100)}
+	colorTemperatureSchema  = ParameterSchema{Description: "2700-6500", validate: validateIntRange(2700, 6500)}
+	colorSchema             = ParameterSchema{Description: "R:G:B (0-255 each)", validate: validateColor}
+	curtainPositionSchema   = ParameterSchema{Description: "index,mode,position (e.g. 0,ff,80)", validate: validateCurtainPosition}
+	blindTiltPositionSchema = ParameterSchema{Description: "direction;position (e.g. up;60)", validate: validateBlindTiltPosition}
+	humidifierModeSchema    = ParameterSchema{Description: "auto, 101, 102, 103 or 0-100", validate: validateHumidifierMode}
+	airConditionerSchema    = ParameterSchema{Description: "temperature,mode,fan speed,power state (e.g. 26,1,3,on)", validate: validateAirConditionerSetAll}
+	vacuumPowerLevelSchema  = ParameterSchema{Description: "0-3", validate: validateIntRange(0, 3)}
+	channelSchema           = ParameterSchema{Description: "channel number", validate: validateIntRange(1, 9999)}
+	fanModeSchema           = ParameterSchema{Description: "direct, natural, sleep or baby", validate: validateFanMode}
+	fanSpeedSchema          = ParameterSchema{Description: "1-100", validate: validateIntRange(1, 100)}
+)
+
+// onOffCommands are the commands shared by most switchable devices.
+var onOffCommands = map[string]ParameterSchema{
+	"turnOn":  defaultParameterSchema,
+	"turnOff": defaultParameterSchema,
+}
+
+// vacuumCommands are the commands supported by the Robot Vacuum Cleaner family.
+var vacuumCommands = map[string]ParameterSchema{
+	"start":    defaultParameterSchema,
+	"stop":     defaultParameterSchema,
+	"dock":     defaultParameterSchema,
+	"PowLevel": vacuumPowerLevelSchema,
+}
+
+// fanCommands are the commands supported by the Circulator Fan family.
+var fanCommands = withCommands(onOffCommands, map[string]ParameterSchema{
+	"setWindMode":  fanModeSchema,
+	"setWindSpeed": fanSpeedSchema,
+})
+
+// tvCommands are the commands supported by TV, Streamer and Set Top Box IR remotes.
+var tvCommands = withCommands(onOffCommands, map[string]ParameterSchema{
+	"SetChannel": channelSchema,
+	"volumeAdd":  defaultParameterSchema,
+	"volumeSub":  defaultParameterSchema,
+	"channelAdd": defaultParameterSchema,
+	"channelSub": defaultParameterSchema,
+})
+
+// mediaPlayerCommands are the commands supported by DVD and Speaker IR remotes.
+var mediaPlayerCommands = withCommands(onOffCommands, map[string]ParameterSchema{
+	"setMute":     defaultParameterSchema,
+	"FastForward": defaultParameterSchema,
+	"Rewind":      defaultParameterSchema,
+	"Next":        defaultParameterSchema,
+	"Previous":    defaultParameterSchema,
+	"Pause":       defaultParameterSchema,
+	"Play":        defaultParameterSchema,
+	"Stop":        defaultParameterSchema,
+	"volumeAdd":   defaultParameterSchema,
+	"volumeSub":   defaultParameterSchema,
+})
+
+// commandSchemas maps deviceType (or IR remoteType) to the parameter schema of each supported command.
+var commandSchemas = map[string]map[string]ParameterSchema{
+	DeviceTypeBot: withCommands(onOffCommands, map[string]ParameterSchema{
+		"press": defaultParameterSchema,
+	}),
+	DeviceTypePlug: onOffCommands,
+	DeviceTypePlugMiniUS: withCommands(onOffCommands, map[string]ParameterSchema{
+		"toggle": defaultParameterSchema,
+	}),
+	DeviceTypePlugMiniJP: withCommands(onOffCommands, map[string]ParameterSchema{
+		"toggle": defaultParameterSchema,
+	}),
+	DeviceTypeCurtain: withCommands(onOffCommands, map[string]ParameterSchema{
+		"pause":       defaultParameterSchema,
+		"setPosition": curtainPositionSchema,
+	}),
+	DeviceTypeCurtain3: withCommands(onOffCommands, map[string]ParameterSchema{
+		"pause":       defaultParameterSchema,
+		"setPosition": curtainPositionSchema,
+	}),
+	DeviceTypeBlindTilt: withCommands(onOffCommands, map[string]ParameterSchema{
+		"setPosition": blindTiltPositionSchema,
+		"fullyOpen":   defaultParameterSchema,
+		"closeUp":     defaultParameterSchema,
+		"closeDown":   defaultParameterSchema,
+	}),
+	DeviceTypeColorBulb: withCommands(onOffCommands, map[string]ParameterSchema{
+		"toggle":              defaultParameterSchema,
+		"setBrightness":       brightnessSchema,
+		"setColor":            colorSchema,
+		"setColorTemperature": colorTemperatureSchema,
+	}),
+	DeviceTypeStripLight: withCommands(onOffCommands, map[string]ParameterSchema{
+		"toggle":        defaultParameterSchema,
+		"setBrightness": brightnessSchema,
+		"setColor":      colorSchema,
+	}),
+	DeviceTypeCeilingLight: withCommands(onOffCommands, map[string]ParameterSchema{
+		"toggle":              defaultParameterSchema,
+		"setBrightness":       brightnessSchema,
+		"setColorTemperature": colorTemperatureSchema,
+	}),
+	DeviceTypeCeilingLightPro: withCommands(onOffCommands, map[string]ParameterSchema{
+		"toggle":              defaultParameterSchema,
+		"setBrightness":       brightnessSchema,
+		"setColorTemperature": colorTemperatureSchema,
+	}),
+	DeviceTypeHumidifier: withCommands(onOffCommands, map[string]ParameterSchema{
+		"setMode": humidifierModeSchema,
+	}),
+	DeviceTypeSmartLock: {
+		"lock":   defaultParameterSchema,
+		"unlock": defaultParameterSchema,
+	},
+	DeviceTypeSmartLockPro: {
+		"lock":     defaultParameterSchema,
+		"unlock":   defaultParameterSchema,
+		"deadbolt": defaultParameterSchema,
+	},
+	DeviceTypeRobotVacuumS1:      vacuumCommands,
+	DeviceTypeRobotVacuumS1Plus:  vacuumCommands,
+	DeviceTypeRobotVacuumK10Plus: vacuumCommands,
+	DeviceTypeBatteryFan:         fanCommands,
+	DeviceTypeCirculatorFan:      fanCommands,
+	// Virtual infrared remotes
+	string(RemoteTypeAirConditioner): withCommands(onOffCommands, map[string]ParameterSchema{
+		"setAll": airConditionerSchema,
+	}),
+	string(RemoteTypeTV):        tvCommands,
+	string(RemoteTypeStreamer):  tvCommands,
+	string(RemoteTypeSetTopBox): tvCommands,
+	string(RemoteTypeDVD):       mediaPlayerCommands,
+	string(RemoteTypeSpeaker):   mediaPlayerCommands,
+	string(RemoteTypeFan): withCommands(onOffCommands, map[string]ParameterSchema{
+		"swing":       defaultParameterSchema,
+		"timer":       defaultParameterSchema,
+		"lowSpeed":    defaultParameterSchema,
+		"middleSpeed": defaultParameterSchema,
+		"highSpeed":   defaultParameterSchema,
+	}),
+}
+
+// withCommands merges command schema maps into a new map.
+func withCommands(sets ...map[string]ParameterSchema) map[string]ParameterSchema {
+	merged := make(map[string]ParameterSchema)
+	for _, set := range sets {
+		for command, schema := range set {
+			merged[command] = schema
+		}
+	}
+	return merged
+}
+
+// CheckParameter validates parameter against the schema registered for deviceType and command.
+// deviceType is the deviceType of a physical device or the remoteType of a virtual IR remote.
+// It returns ErrUnknownCommand if no schema is registered, and ErrInvalidParameter if the
+// parameter's structure or range is wrong. A nil parameter is treated as "default", as in SendDeviceCommand.
+func (c *Client) CheckParameter(deviceType, command string, parameter interface{}) error {
+	schema, ok := commandSchemas[deviceType][command]
+	if !ok {
+		return fmt.Errorf("%w: %s %q", ErrUnknownCommand, deviceType, command)
+	}
+	if parameter == nil {
+		parameter = "default"
+	}
+	if err := schema.Validate(parameter); err != nil {
+		return fmt.Errorf("%w: %s %q expects %s: %v", ErrInvalidParameter, deviceType, command, schema.Description, err)
+	}
+	return nil
+}
+
+// CommandSpec describes a command supported by a device type.
+type CommandSpec struct {
+	Name                 string // Command name, e.g. "setBrightness"
+	NeedsParameter       bool   // False if the command only takes "default"
+	ParameterDescription string // Format of the parameter, e.g. "1-100"; "default" if none is needed
+	_                    struct{}
+}
+
+// SupportedCommands returns the commands registered for deviceType (or the remoteType of a virtual
+// IR remote), sorted by name. This is static metadata from the built-in registry used by CheckParameter
+// and WithCommandValidation; no API call is made. It returns nil for unregistered device types.
+// Customize commands of IR remotes are user-defined and never listed.
+func SupportedCommands(deviceType DeviceType) []CommandSpec {
+	commands, ok := commandSchemas[string(deviceType)]
+	if !ok {
+		return nil
+	}
+	specs := make([]CommandSpec, 0, len(commands))
+	for _, name := range slices.Sorted(maps.Keys(commands)) {
+		schema := commands[name]
+		specs = append(specs, CommandSpec{
+			Name:                 name,
+			NeedsParameter:       schema.Description != defaultParameterSchema.Description,
+			ParameterDescription: schema.Description,
+		})
+	}
+	return specs
+}
+
+// --- Validators ---
+
+func validateDefaultParameter(parameter interface{}) error {
+	if s, ok := parameter.(string); ok && s == "default" {
+		return nil
+	}
+	return fmt.Errorf("got %v", parameter)
+}
+
+// parameterInt converts numeric parameters (including numeric strings) to an int.
+func parameterInt(parameter interface{}) (int, error) {
+	switch v := parameter.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		return int(v), nil
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("%q is not an integer", v)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("unsupported parameter type %T", parameter)
+}
+
+func validateIntRange(min, max int) func(interface{}) error {
+	return func(parameter interface{}) error {
+		n, err := parameterInt(parameter)
+		if err != nil {
+			return err
+		}
+		return checkRange("value", n, min, max)
+	}
+}
+
+func checkRange(name string, n, min, max int) error {
+	if n < min || n > max {
+		return fmt.Errorf("%s %d out of range %d-%d", name, n, min, max)
+	}
+	return nil
+}
+
+// splitParameter splits a string parameter into exactly n parts.
+func splitParameter(parameter interface{}, sep string, n int) ([]string, error) {
+	s, ok := parameter.(string)
+	if !ok {
+		return nil, fmt.Errorf("unsupported parameter type %T", parameter)
+	}
+	parts := strings.Split(s, sep)
+	if len(parts) != n {
+		return nil, fmt.Errorf("%q must have %d %q-separated parts", s, n, sep)
+	}
+	return parts, nil
+}
+
+func validateColor(parameter interface{}) error {
+	parts, err := splitParameter(parameter, ":", 3)
+	if err != nil {
+		return err
+	}
+	for _, part := range parts {
+		n, err := parameterInt(part)
+		if err != nil {
+			return err
+		}
+		if err := checkRange("color component", n, 0, 255); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCurtainPosition(parameter interface{}) error {
+	parts, err := splitParameter(parameter, ",", 3)
+	if err != nil {
+		return err
+	}
+	switch CurtainMode(parts[1]) {
+	case CurtainModePerformance, CurtainModeSilent, CurtainModeDefault:
+	default:
+		return fmt.Errorf("mode %q must be 0, 1 or ff", parts[1])
+	}
+	position, err := parameterInt(parts[2])
+	if err != nil {
+		return err
+	}
+	return checkRange("position", position, 0, 100)
+}
+
+func validateBlindTiltPosition(parameter interface{}) error {
+	parts, err := splitParameter(parameter, ";", 2)
+	if err != nil {
+		return err
+	}
+	if parts[0] != "up" && parts[0] != "down" {
+		return fmt.Errorf("direction %q must be up or down", parts[0])
+	}
+	position, err := parameterInt(parts[1])
+	if err != nil {
+		return err
+	}
+	if err := checkRange("position", position, 0, 100); err != nil {
+		return err
+	}
+	if position%2 != 0 {
+		return fmt.Errorf("position %d must be a multiple of 2", position)
+	}
+	return nil
+}
+
+func validateHumidifierMode(parameter interface{}) error {
+	if s, ok := parameter.(string); ok && s == "auto" {
+		return nil
+	}
+	n, err := parameterInt(parameter)
+	if err != nil {
+		return err
+	}
+	if n >= 101 && n <= 103 {
+		return nil
+	}
+	return checkRange("humidity", n, 0, 100)
+}
+
+func validateFanMode(parameter interface{}) error {
+	switch parameter {
+	case string(FanModeDirect), string(FanModeNatural), string(FanModeSleep), string(FanModeBaby):
+		return nil
+	}
+	return fmt.Errorf("got %v", parameter)
+}
+
+func validateAirConditionerSetAll(parameter interface{}) error {
+	parts, err := splitParameter(parameter, ",", 4)
+	if err != nil {
+		return err
+	}
+	temperature, err := parameterInt(parts[0])
+	if err != nil {
+		return err
+	}
+	if err := checkRange("temperature", temperature, 16, 30); err != nil {
+		return err
+	}
+	mode, err := parameterInt(parts[1])
+	if err != nil {
+		return err
+	}
+	if err := checkRange("mode", mode, 1, 5); err != nil {
+		return err
+	}
+	fan, err := parameterInt(parts[2])
+	if err != nil {
+		return err
+	}
+	if err := checkRange("fan speed", fan, 1, 4); err != nil {
+		return err
+	}
+	if parts[3] != "on" && parts[3] != "off" {
+		return fmt.Errorf("power state %q must be on or off", parts[3])
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package switchbot
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCheckParameter(t *testing.T) {
+	client, err := NewClient("token", "secret")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		deviceType string
+		command    string
+		parameter  interface{}
+		wantErr    error
+	}{
+		{"BotPressDefault", DeviceTypeBot, "press", "default", nil},
+		{"BotPressNil", DeviceTypeBot, "press", nil, nil},
+		{"BotPressUnexpectedParameter", DeviceTypeBot, "press", 5, ErrInvalidParameter},
+		{"BrightnessInt", DeviceTypeColorBulb, "setBrightness", 50, nil},
+		{"BrightnessJSONNumber", DeviceTypeColorBulb, "setBrightness", float64(100), nil},
+		{"BrightnessOutOfRange", DeviceTypeColorBulb, "setBrightness", 0, ErrInvalidParameter},
+		{"BrightnessWrongType", DeviceTypeColorBulb, "setBrightness", []int{50}, ErrInvalidParameter},
+		{"Color", DeviceTypeStripLight, "setColor", "255:128:0", nil},
+		{"ColorOutOfRange", DeviceTypeStripLight, "setColor", "256:0:0", ErrInvalidParameter},
+		{"ColorMalformed", DeviceTypeStripLight, "setColor", "255,0,0", ErrInvalidParameter},
+		{"ColorTemperature", DeviceTypeCeilingLight, "setColorTemperature", 4000, nil},
+		{"ColorTemperatureOutOfRange", DeviceTypeCeilingLight, "setColorTemperature", 9000, ErrInvalidParameter},
+		{"CurtainPosition", DeviceTypeCurtain, "setPosition", "0,ff,80", nil},
+		{"CurtainBadMode", DeviceTypeCurtain, "setPosition", "0,2,80", ErrInvalidParameter},
+		{"CurtainBadPosition", DeviceTypeCurtain3, "setPosition", "0,1,120", ErrInvalidParameter},
+		{"BlindTiltPosition", DeviceTypeBlindTilt, "setPosition", "up;60", nil},
+		{"BlindTiltOddPosition", DeviceTypeBlindTilt, "setPosition", "down;61", ErrInvalidParameter},
+		{"HumidifierAuto", DeviceTypeHumidifier, "setMode", "auto", nil},
+		{"HumidifierPreset", DeviceTypeHumidifier, "setMode", 102, nil},
+		{"HumidifierOutOfRange", DeviceTypeHumidifier, "setMode", 104, ErrInvalidParameter},
+		{"AirConditionerSetAll", "Air Conditioner", "setAll", "26,1,3,on", nil},
+		{"AirConditionerBadPower", "Air Conditioner", "setAll", "26,1,3,maybe", ErrInvalidParameter},
+		{"AirConditionerMissingPart", "Air Conditioner", "setAll", "26,1,on", ErrInvalidParameter},
+		{"FanWindMode", DeviceTypeBatteryFan, "setWindMode", "natural", nil},
+		{"FanWindModeUnknown", DeviceTypeCirculatorFan, "setWindMode", "turbo", ErrInvalidParameter},
+		{"FanWindSpeedOutOfRange", DeviceTypeBatteryFan, "setWindSpeed", 0, ErrInvalidParameter},
+		{"FanOscillationUndocumented", DeviceTypeCirculatorFan, "setOscillation", "on", ErrUnknownCommand},
+		{"UnknownCommand", DeviceTypeBot, "setBrightness", 50, ErrUnknownCommand},
+		{"UnknownDeviceType", "Toaster", "turnOn", nil, ErrUnknownCommand},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := client.CheckParameter(tc.deviceType, tc.command, tc.parameter)
+			if tc.wantErr == nil {
+				if err != nil {
+					t.Errorf("CheckParameter() returned error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("CheckParameter() error = %v; want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSupportedCommands(t *testing.T) {
+	specs := SupportedCommands(DeviceTypeStripLight)
+	var names []string
+	for _, spec := range specs {
+		names = append(names, spec.Name)
+	}
+	want := []string{"setBrightness", "setColor", "toggle", "turnOff", "turnOn"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("SupportedCommands(Strip Light) names = %v; want %v", names, want)
+	}
+	if !specs[0].NeedsParameter || specs[0].ParameterDescription != "1-100" {
+		t.Errorf("setBrightness spec = %+v; want a 1-100 parameter", specs[0])
+	}
+	if specs[4].NeedsParameter {
+		t.Errorf("turnOn spec = %+v; want no parameter", specs[4])
+	}
+
+	if specs := SupportedCommands("Unknown"); specs != nil {
+		t.Errorf("SupportedCommands(Unknown) = %v; want nil", specs)
+	}
+}
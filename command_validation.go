@@ -0,0 +1,83 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// WithCommandValidation makes SendDeviceCommandValidated reject command names that the device type
+// does not support before anything is sent, using the same per-deviceType registry as CheckParameter.
+// Device types without a registry entry and customize commands are sent unvalidated.
+func WithCommandValidation() ClientOption {
+	return func(c *Client) error {
+		c.commandValidation = true
+		return nil
+	}
+}
+
+// SendDeviceCommandValidated is SendDeviceCommand for a device whose type is known, e.g. from GetDevices.
+// With WithCommandValidation, a command the device type does not support returns ErrUnknownCommand,
+// listing the supported commands and suggesting the closest match, instead of failing at the API
+// with statusCode 160.
+func (c *Client) SendDeviceCommandValidated(ctx context.Context, deviceID string, deviceType DeviceType, command string, parameter interface{}, commandType string) (CommandResponse, error) {
+	if c.commandValidation && commandType != string(CommandTypeCustomize) {
+		if err := validateCommandName(deviceType, command); err != nil {
+			return nil, err
+		}
+	}
+	return c.SendDeviceCommand(ctx, deviceID, command, parameter, commandType)
+}
+
+// validateCommandName checks command against the registry for deviceType.
+// Unregistered device types are accepted.
+func validateCommandName(deviceType DeviceType, command string) error {
+	commands, ok := commandSchemas[string(deviceType)]
+	if !ok {
+		return nil
+	}
+	if _, ok := commands[command]; ok {
+		return nil
+	}
+	supported := slices.Sorted(maps.Keys(commands))
+	if suggestion := closestCommand(command, supported); suggestion != "" {
+		return fmt.Errorf("%w: %s does not support %q; did you mean %q? (supported: %s)",
+			ErrUnknownCommand, string(deviceType), command, suggestion, strings.Join(supported, ", "))
+	}
+	return fmt.Errorf("%w: %s does not support %q (supported: %s)",
+		ErrUnknownCommand, string(deviceType), command, strings.Join(supported, ", "))
+}
+
+// closestCommand returns the candidate within two edits of command (ignoring case), or "" if none is.
+func closestCommand(command string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if d := editDistance(strings.ToLower(command), strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,77 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSendDeviceCommandValidated(t *testing.T) {
+	t.Run("TypoRejected", func(t *testing.T) {
+		var got []capturedCommand
+		_, server := setupMockServer(t, commandCaptureHandler(t, &got))
+		client, err := NewClient("token", "secret", WithBaseURL(server.URL), WithCommandValidation())
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+
+		_, err = client.SendDeviceCommandValidated(context.Background(), "B1", DeviceTypeBot, "turnOnn", nil, "")
+		if !errors.Is(err, ErrUnknownCommand) {
+			t.Fatalf("SendDeviceCommandValidated() error = %v; want ErrUnknownCommand", err)
+		}
+		for _, want := range []string{`did you mean "turnOn"`, "press, turnOff, turnOn"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q does not contain %q", err, want)
+			}
+		}
+
+		_, err = client.SendDeviceCommandValidated(context.Background(), "B1", DeviceTypeBot, "setBrightness", 50, "")
+		if !errors.Is(err, ErrUnknownCommand) || strings.Contains(err.Error(), "did you mean") {
+			t.Errorf("SendDeviceCommandValidated() error = %v; want ErrUnknownCommand without a suggestion", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("sent %d commands; rejected commands should not be sent", len(got))
+		}
+	})
+
+	t.Run("PassThrough", func(t *testing.T) {
+		var got []capturedCommand
+		_, server := setupMockServer(t, commandCaptureHandler(t, &got))
+		client, err := NewClient("token", "secret", WithBaseURL(server.URL), WithCommandValidation())
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+
+		calls := []struct {
+			deviceType  DeviceType
+			command     string
+			commandType string
+		}{
+			{DeviceTypeBot, "press", ""},       // Supported command
+			{"Toaster", "toast", ""},           // Unregistered device type
+			{"DIY TV", "Netflix", "customize"}, // Customize commands are user-defined
+			{DeviceTypeBot, "myButton", "customize"},
+		}
+		for _, call := range calls {
+			if _, err := client.SendDeviceCommandValidated(context.Background(), "D1", call.deviceType, call.command, nil, call.commandType); err != nil {
+				t.Errorf("SendDeviceCommandValidated(%s, %s) returned error: %v", string(call.deviceType), call.command, err)
+			}
+		}
+		if len(got) != len(calls) {
+			t.Errorf("sent %d commands; want %d", len(got), len(calls))
+		}
+	})
+
+	t.Run("ValidationOff", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+		if _, err := client.SendDeviceCommandValidated(context.Background(), "B1", DeviceTypeBot, "turnOnn", nil, ""); err != nil {
+			t.Fatalf("SendDeviceCommandValidated() returned error: %v", err)
+		}
+		if len(got) != 1 {
+			t.Errorf("sent %d commands; want 1 without WithCommandValidation", len(got))
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrVerifyTimeout is returned by SendCommandAndVerify when the device status does not reach the expected state in time.
+var ErrVerifyTimeout = errors.New("timed out verifying device status")
+
+const (
+	defaultCommandMaxWait      = 30 * time.Second
+	defaultCommandPollInterval = time.Second
+)
+
+// CommandID returns the commandId of an asynchronous command, or "" if the command completed synchronously.
+// The API offers no endpoint to query a command by its ID; use SendCommandAndVerify to confirm the effect
+// of a command through GetDeviceStatus.
+func (r CommandResponse) CommandID() string {
+	id, _ := r["commandId"].(string)
+	return id
+}
+
+// SendCommandAndVerify sends command (with the default parameter) and then polls GetDeviceStatus
+// every second until expectFn accepts the status, confirming the command took effect, e.g.:
+//
+//	err := client.SendCommandAndVerify(ctx, id, "turnOn", func(s DeviceStatus) bool { return s["power"] == "on" }, 30*time.Second)
+//
+// It returns ErrVerifyTimeout if timeout elapses first (a device that silently ignored the command),
+// the *APIError if the command or a status request fails, and ctx.Err() if the caller's context ends first.
+// A timeout <= 0 uses a 30s default.
+func (c *Client) SendCommandAndVerify(ctx context.Context, deviceID, command string, expectFn func(DeviceStatus) bool, timeout time.Duration) error {
+	if expectFn == nil {
+		return fmt.Errorf("expectFn cannot be nil")
+	}
+	if timeout <= 0 {
+		timeout = defaultCommandMaxWait
+	}
+	if _, err := c.SendDeviceCommand(ctx, deviceID, command, nil, ""); err != nil {
+		return err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// timedOut distinguishes our own timeout from the caller's context ending.
+	timedOut := func() bool {
+		return ctx.Err() == nil && waitCtx.Err() != nil
+	}
+
+	for {
+		status, err := c.GetDeviceStatus(waitCtx, deviceID)
+		if err != nil {
+			if timedOut() {
+				return fmt.Errorf("%w: %s on device %s", ErrVerifyTimeout, command, deviceID)
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if expectFn(status) {
+			return nil
+		}
+
+		if err := c.sleep(waitCtx, defaultCommandPollInterval); err != nil {
+			if timedOut() {
+				return fmt.Errorf("%w: %s on device %s", ErrVerifyTimeout, command, deviceID)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendCommandAndVerify(t *testing.T) {
+	// verifyHandler accepts commands and reports power "on" from the onAfter-th status poll onwards.
+	verifyHandler := func(onAfter int32, polls *int32) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				statusHandler(`{}`)(w, r)
+				return
+			}
+			power := "off"
+			if atomic.AddInt32(polls, 1) >= onAfter {
+				power = "on"
+			}
+			statusHandler(fmt.Sprintf(`{"deviceId": "B1", "deviceType": "Bot", "power": %q}`, power))(w, r)
+		}
+	}
+	newClient := func(t *testing.T, handler http.HandlerFunc) *Client {
+		_, server := setupMockServer(t, handler)
+		noSleep := func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+		client, err := NewClient("token", "secret", WithBaseURL(server.URL), WithSleeper(noSleep))
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+		return client
+	}
+	isOn := func(s DeviceStatus) bool { return s["power"] == "on" }
+
+	t.Run("Verified", func(t *testing.T) {
+		var polls int32
+		client := newClient(t, verifyHandler(3, &polls))
+
+		if err := client.SendCommandAndVerify(context.Background(), "B1", "turnOn", isOn, 2*time.Second); err != nil {
+			t.Fatalf("SendCommandAndVerify() returned error: %v", err)
+		}
+		if polls != 3 {
+			t.Errorf("status polled %d times; want 3", polls)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		var polls int32
+		client := newClient(t, verifyHandler(1<<30, &polls))
+
+		err := client.SendCommandAndVerify(context.Background(), "B1", "turnOn", isOn, 50*time.Millisecond)
+		if !errors.Is(err, ErrVerifyTimeout) {
+			t.Errorf("SendCommandAndVerify() error = %v; want ErrVerifyTimeout", err)
+		}
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, `{"statusCode": 161, "message": "device offline", "body": {}}`)
+		})
+
+		err := client.SendCommandAndVerify(context.Background(), "B1", "turnOn", isOn, time.Second)
+		if !errors.Is(err, ErrDeviceOffline) || errors.Is(err, ErrVerifyTimeout) {
+			t.Errorf("SendCommandAndVerify() error = %v; want ErrDeviceOffline", err)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+)
+
+// CurtainDevice controls a single Curtain or Curtain3 device.
+type CurtainDevice struct {
+	client   *Client
+	DeviceID string
+	_        struct{}
+}
+
+// Curtain returns a handle for controlling the Curtain with the given device ID.
+func (c *Client) Curtain(deviceID string) *CurtainDevice {
+	return &CurtainDevice{client: c, DeviceID: deviceID}
+}
+
+// Open fully opens the curtain.
+func (d *CurtainDevice) Open(ctx context.Context) error {
+	_, err := d.client.SendDeviceCommand(ctx, d.DeviceID, "turnOn", nil, "")
+	return err
+}
+
+// Close fully closes the curtain.
+func (d *CurtainDevice) Close(ctx context.Context) error {
+	_, err := d.client.SendDeviceCommand(ctx, d.DeviceID, "turnOff", nil, "")
+	return err
+}
+
+// Pause stops the curtain if it is moving.
+func (d *CurtainDevice) Pause(ctx context.Context) error {
+	_, err := d.client.SendDeviceCommand(ctx, d.DeviceID, "pause", nil, "")
+	return err
+}
+
+// SetPosition moves the curtain to position using the given motor mode.
+// As in the API, position 0 means fully open and 100 means fully closed.
+func (d *CurtainDevice) SetPosition(ctx context.Context, position int, mode CurtainMode) error {
+	if err := checkRange("position", position, 0, 100); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	if mode == "" {
+		mode = CurtainModeDefault
+	}
+	parameter := fmt.Sprintf("0,%s,%d", string(mode), position)
+	_, err := d.client.SendDeviceCommand(ctx, d.DeviceID, "setPosition", parameter, "")
+	return err
+}
+
+// CurtainPreset is a named curtain position, expressed as how far open the curtain is (percent).
+type CurtainPreset int
+
+const (
+	CurtainPresetClosed CurtainPreset = 0
+	CurtainPresetHalf   CurtainPreset = 50
+	CurtainPresetOpen   CurtainPreset = 100
+)
+
+// Position converts the preset to the API position, where 0 is fully open and 100 is fully closed.
+func (p CurtainPreset) Position() int {
+	return 100 - int(p)
+}
+
+// String implements fmt.Stringer.
+func (p CurtainPreset) String() string {
+	switch p {
+	case CurtainPresetClosed:
+		return "closed"
+	case CurtainPresetHalf:
+		return "half"
+	case CurtainPresetOpen:
+		return "open"
+	}
+	return fmt.Sprintf("%d%% open", int(p))
+}
+
+// SetPreset moves the curtain to a named preset position using the default motor mode.
+func (d *CurtainDevice) SetPreset(ctx context.Context, preset CurtainPreset) error {
+	return d.SetPosition(ctx, preset.Position(), CurtainModeDefault)
+}
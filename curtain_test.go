@@ -0,0 +1,52 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCurtainDevice_SetPreset(t *testing.T) {
+	testCases := []struct {
+		preset        CurtainPreset
+		wantParameter string
+	}{
+		{CurtainPresetOpen, "0,ff,0"},
+		{CurtainPresetHalf, "0,ff,50"},
+		{CurtainPresetClosed, "0,ff,100"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.preset.String(), func(t *testing.T) {
+			var got []capturedCommand
+			client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+			if err := client.Curtain("C1").SetPreset(context.Background(), tc.preset); err != nil {
+				t.Fatalf("SetPreset() returned error: %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("received %d commands; want 1", len(got))
+			}
+			if got[0].Command != "setPosition" || got[0].Parameter != tc.wantParameter {
+				t.Errorf("command = %s(%v); want setPosition(%s)", got[0].Command, got[0].Parameter, tc.wantParameter)
+			}
+		})
+	}
+}
+
+func TestCurtainDevice_SetPosition(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+	curtain := client.Curtain("C1")
+
+	if err := curtain.SetPosition(context.Background(), 30, CurtainModeSilent); err != nil {
+		t.Fatalf("SetPosition() returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].Parameter != "0,1,30" {
+		t.Errorf("received %+v; want setPosition(0,1,30)", got)
+	}
+
+	if err := curtain.SetPosition(context.Background(), 101, CurtainModeDefault); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("SetPosition(101) error = %v; want ErrInvalidParameter", err)
+	}
+}
@@ -0,0 +1,86 @@
+package switchbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// maxBodySnippet is how many bytes of a body are quoted in decode errors.
+const maxBodySnippet = 256
+
+// decodeBody unmarshals a response body into v. On failure the error names the target type,
+// points out an array/object mismatch, and quotes the start of the body.
+func decodeBody(raw json.RawMessage, v any) error {
+	return describeDecodeError(raw, v, json.Unmarshal(raw, v))
+}
+
+// decodeBodyUseNumber is decodeBody with numbers in interface{} values decoded as json.Number.
+func decodeBodyUseNumber(raw json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return describeDecodeError(raw, v, dec.Decode(v))
+}
+
+// decodeMapBody decodes a body holding free-form maps (device lists and statuses),
+// honoring WithJSONNumbers.
+func (c *Client) decodeMapBody(raw json.RawMessage, v any) error {
+	if c.useNumber {
+		return decodeBodyUseNumber(raw, v)
+	}
+	return decodeBody(raw, v)
+}
+
+// describeDecodeError wraps a failure to decode raw into v; a nil err is returned as is.
+func describeDecodeError(raw json.RawMessage, v any, err error) error {
+	if err == nil {
+		return nil
+	}
+	target := reflect.TypeOf(v)
+	if target != nil && target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+	if got, want := jsonKind(raw), targetJSONKind(target); got != "" && want != "" && got != want {
+		err = fmt.Errorf("expected a JSON %s, got %s: %w", want, got, err)
+	}
+	return fmt.Errorf("failed to unmarshal body into %v: %w, body: %s", target, err, bodySnippet(raw))
+}
+
+// jsonKind returns "object" or "array" for the top-level JSON value in raw, or "" for anything else.
+func jsonKind(raw []byte) string {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return ""
+	}
+	switch trimmed[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	}
+	return ""
+}
+
+// targetJSONKind returns the JSON kind a value of type t decodes from, or "" if it is not
+// a composite type or implements json.Unmarshaler.
+func targetJSONKind(t reflect.Type) string {
+	if t == nil || reflect.PointerTo(t).Implements(reflect.TypeFor[json.Unmarshaler]()) {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return ""
+}
+
+// bodySnippet returns raw as a string, truncated to maxBodySnippet bytes.
+func bodySnippet(raw []byte) string {
+	if len(raw) <= maxBodySnippet {
+		return string(raw)
+	}
+	return fmt.Sprintf("%s... (%d bytes total)", raw[:maxBodySnippet], len(raw))
+}
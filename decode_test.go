@@ -0,0 +1,69 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBody(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var scenes []Scene
+		if err := decodeBody(json.RawMessage(`[{"sceneId": "S1"}]`), &scenes); err != nil {
+			t.Fatalf("decodeBody() returned error: %v", err)
+		}
+		if len(scenes) != 1 || scenes[0].SceneID != "S1" {
+			t.Errorf("decodeBody() decoded %+v", scenes)
+		}
+	})
+
+	t.Run("ArrayForObject", func(t *testing.T) {
+		var resp GetDevicesResponse
+		err := decodeBody(json.RawMessage(`[{"deviceId": "D1"}]`), &resp)
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("decodeBody() error = %v; want it to wrap *json.UnmarshalTypeError", err)
+		}
+		for _, want := range []string{"switchbot.GetDevicesResponse", "expected a JSON object, got array", `body: [{"deviceId": "D1"}]`} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("decodeBody() error %q does not contain %q", err, want)
+			}
+		}
+	})
+
+	t.Run("ObjectForArray", func(t *testing.T) {
+		var details []WebhookDetails
+		err := decodeBody(json.RawMessage(`{"url": "https://example.com"}`), &details)
+		if err == nil || !strings.Contains(err.Error(), "expected a JSON array, got object") {
+			t.Errorf("decodeBody() error = %v; want an array/object hint", err)
+		}
+	})
+
+	t.Run("UnmarshalerHasNoHint", func(t *testing.T) {
+		var n FlexInt
+		err := decodeBody(json.RawMessage(`{}`), &n)
+		if err == nil || strings.Contains(err.Error(), "expected a JSON") {
+			t.Errorf("decodeBody() error = %v; want no shape hint for custom unmarshalers", err)
+		}
+	})
+
+	t.Run("LongBodyTruncated", func(t *testing.T) {
+		raw := json.RawMessage(`"` + strings.Repeat("x", 1000) + `"`)
+		var status DeviceStatus
+		err := decodeBody(raw, &status)
+		if err == nil || !strings.Contains(err.Error(), "... (1002 bytes total)") || strings.Contains(err.Error(), strings.Repeat("x", maxBodySnippet)) {
+			t.Errorf("decodeBody() error = %v; want a truncated body", err)
+		}
+	})
+}
+
+func TestGetDevices_UnexpectedArrayBody(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(`[{"deviceId": "D1"}]`))
+
+	_, err := client.GetDevices(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), "GetDevices response: ") || !strings.Contains(err.Error(), "expected a JSON object, got array") {
+		t.Errorf("GetDevices() error = %v; want a descriptive decode error", err)
+	}
+}
@@ -0,0 +1,48 @@
+package switchbot
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotConfigured is returned by Default when Configure has not succeeded yet.
+var ErrNotConfigured = errors.New("default client not configured: call Configure first")
+
+// defaultClient is the package-level client set by Configure.
+var defaultClient struct {
+	mu     sync.RWMutex
+	client *Client
+}
+
+// Configure creates a client with NewClient and makes it the package-level default returned by Default.
+// It is safe to call concurrently and may be called again to replace the default.
+// On error the previous default is kept.
+func Configure(token, secret string, options ...ClientOption) error {
+	client, err := NewClient(token, secret, options...)
+	if err != nil {
+		return err
+	}
+	setDefaultClient(client)
+	return nil
+}
+
+// Default returns the client set by Configure, or ErrNotConfigured if Configure has not succeeded yet.
+//
+//	if err := switchbot.Configure(token, secret); err != nil { ... }
+//	client, err := switchbot.Default()
+//	if err != nil { ... }
+//	devices, err := client.GetDevices(ctx)
+func Default() (*Client, error) {
+	defaultClient.mu.RLock()
+	defer defaultClient.mu.RUnlock()
+	if defaultClient.client == nil {
+		return nil, ErrNotConfigured
+	}
+	return defaultClient.client, nil
+}
+
+func setDefaultClient(client *Client) {
+	defaultClient.mu.Lock()
+	defer defaultClient.mu.Unlock()
+	defaultClient.client = client
+}
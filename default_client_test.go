@@ -0,0 +1,64 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestDefaultClient(t *testing.T) {
+	t.Cleanup(func() { setDefaultClient(nil) })
+	setDefaultClient(nil)
+
+	if client, err := Default(); client != nil || !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("Default() before Configure = %v, %v; want nil, ErrNotConfigured", client, err)
+	}
+
+	if err := Configure("", "secret"); err == nil {
+		t.Error("Configure() with empty token returned nil error")
+	}
+	if _, err := Default(); !errors.Is(err, ErrNotConfigured) {
+		t.Error("failed Configure() set a default client")
+	}
+
+	_, server := setupMockServer(t, statusHandler(`{"deviceList": [{"deviceId": "D1"}], "infraredRemoteList": []}`))
+	if err := Configure("token", "secret", WithBaseURL(server.URL)); err != nil {
+		t.Fatalf("Configure() returned error: %v", err)
+	}
+	first, err := Default()
+	if err != nil {
+		t.Fatalf("Default() after Configure returned error: %v", err)
+	}
+	devices, err := first.GetDevices(context.Background())
+	if err != nil {
+		t.Fatalf("GetDevices() on the default client returned error: %v", err)
+	}
+	if len(devices.DeviceList) != 1 {
+		t.Errorf("got %d devices; want 1", len(devices.DeviceList))
+	}
+
+	if err := Configure("", ""); err == nil {
+		t.Error("Configure() with empty credentials returned nil error")
+	}
+	if current, _ := Default(); current != first {
+		t.Error("failed Configure() replaced the default client")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = Configure("token", "secret", WithBaseURL(server.URL))
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = Default()
+		}()
+	}
+	wg.Wait()
+	if current, _ := Default(); current == first {
+		t.Error("Configure() did not replace the default client")
+	}
+}
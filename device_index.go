@@ -0,0 +1,72 @@
+package switchbot
+
+import (
+	"context"
+	"strings"
+)
+
+// DeviceIndex maps device IDs to names and back for both physical devices and IR remotes.
+// It is a snapshot of the device list and is safe for concurrent use; build a new one to refresh it.
+type DeviceIndex struct {
+	nameByID map[string]string
+	idByName map[string]string // Keyed by lower-cased name
+}
+
+// NewDeviceIndex builds a DeviceIndex from a device list, e.g. one returned by GetDevices.
+// When several devices share a name, IDByName returns the first in list order, physical devices first.
+func NewDeviceIndex(devices *GetDevicesResponse) *DeviceIndex {
+	idx := &DeviceIndex{
+		nameByID: make(map[string]string),
+		idByName: make(map[string]string),
+	}
+	if devices == nil {
+		return idx
+	}
+	for _, d := range devices.DeviceList {
+		id, _ := d["deviceId"].(string)
+		name, _ := d["deviceName"].(string)
+		idx.add(id, name)
+	}
+	for _, ir := range devices.InfraredRemoteList {
+		idx.add(ir.DeviceID, ir.DeviceName)
+	}
+	return idx
+}
+
+func (idx *DeviceIndex) add(id, name string) {
+	if id == "" {
+		return
+	}
+	idx.nameByID[id] = name
+	key := strings.ToLower(name)
+	if _, exists := idx.idByName[key]; !exists && name != "" {
+		idx.idByName[key] = id
+	}
+}
+
+// BuildDeviceIndex fetches the device list once and returns an index for resolving names,
+// so the device-list call need not be repeated for every lookup.
+func (c *Client) BuildDeviceIndex(ctx context.Context) (*DeviceIndex, error) {
+	devices, err := c.GetDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewDeviceIndex(devices), nil
+}
+
+// NameByID returns the name of the device with the given ID.
+func (idx *DeviceIndex) NameByID(id string) (string, bool) {
+	name, ok := idx.nameByID[id]
+	return name, ok
+}
+
+// IDByName returns the ID of the device with the given name (case-insensitive).
+func (idx *DeviceIndex) IDByName(name string) (string, bool) {
+	id, ok := idx.idByName[strings.ToLower(name)]
+	return id, ok
+}
+
+// Len returns the number of devices in the index.
+func (idx *DeviceIndex) Len() int {
+	return len(idx.nameByID)
+}
@@ -0,0 +1,54 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestBuildDeviceIndex(t *testing.T) {
+	body := `{"deviceList": [
+		{"deviceId": "B1", "deviceName": "Kettle", "deviceType": "Bot"},
+		{"deviceId": "M1", "deviceName": "Bedroom Meter", "deviceType": "Meter"},
+		{"deviceId": "B2", "deviceName": "kettle", "deviceType": "Bot"}
+	], "infraredRemoteList": [
+		{"deviceId": "IR1", "deviceName": "Living Room TV", "remoteType": "TV"}
+	]}`
+	var calls int
+	client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		statusHandler(body)(w, r)
+	})
+
+	idx, err := client.BuildDeviceIndex(context.Background())
+	if err != nil {
+		t.Fatalf("BuildDeviceIndex() returned error: %v", err)
+	}
+	if idx.Len() != 4 {
+		t.Errorf("Len() = %d; want 4", idx.Len())
+	}
+
+	for id, want := range map[string]string{"B1": "Kettle", "IR1": "Living Room TV", "B2": "kettle"} {
+		if got, ok := idx.NameByID(id); !ok || got != want {
+			t.Errorf("NameByID(%q) = %q, %v; want %q", id, got, ok, want)
+		}
+	}
+	for name, want := range map[string]string{"bedroom meter": "M1", "LIVING ROOM TV": "IR1", "kettle": "B1"} {
+		if got, ok := idx.IDByName(name); !ok || got != want {
+			t.Errorf("IDByName(%q) = %q, %v; want %q", name, got, ok, want)
+		}
+	}
+	if _, ok := idx.IDByName("Garage"); ok {
+		t.Error("IDByName(Garage) found a device")
+	}
+	if _, ok := idx.NameByID("X9"); ok {
+		t.Error("NameByID(X9) found a device")
+	}
+
+	// Lookups are served from the index without further API calls.
+	idx.NameByID("B1")
+	idx.IDByName("Kettle")
+	if calls != 1 {
+		t.Errorf("made %d API calls; want 1", calls)
+	}
+}
@@ -0,0 +1,65 @@
+package switchbot
+
+import (
+	"context"
+	"sync"
+)
+
+// DeviceStatusResult is a single result delivered by StreamDeviceStatuses.
+type DeviceStatusResult struct {
+	DeviceID string
+	Status   DeviceStatus
+	Err      error
+	_        struct{}
+}
+
+// StreamDeviceStatuses fetches the status of each device with up to concurrency requests in flight,
+// delivering results on the returned channel as they arrive (not in input order).
+// The channel is closed once all devices have been reported or ctx is cancelled.
+// Workers stop when ctx is cancelled, so cancel ctx if you stop reading before the channel closes.
+// A panic while fetching a status is delivered as a *PanicError for that device.
+func (c *Client) StreamDeviceStatuses(ctx context.Context, deviceIDs []string, concurrency int) <-chan DeviceStatusResult {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > len(deviceIDs) {
+		concurrency = len(deviceIDs)
+	}
+
+	results := make(chan DeviceStatusResult)
+	jobs := make(chan string)
+
+	go func() {
+		defer close(jobs)
+		for _, id := range deviceIDs {
+			select {
+			case jobs <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				status, err := c.getDeviceStatusProtected(ctx, id)
+				select {
+				case results <- DeviceStatusResult{DeviceID: id, Status: status, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
@@ -0,0 +1,91 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamDeviceStatuses(t *testing.T) {
+	var inFlight, maxInFlight int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		id := strings.Split(r.URL.Path, "/")[3]
+		if id == "BAD" {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"statusCode": 152, "message": "device not found", "body": {}}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"statusCode": 100, "message": "success", "body": {"deviceId": %q, "power": "on"}}`, id)
+	}
+	client, _ := setupMockServer(t, handler)
+
+	ids := []string{"D1", "D2", "D3", "BAD", "D5", "D6"}
+	got := make(map[string]DeviceStatusResult)
+	for result := range client.StreamDeviceStatuses(context.Background(), ids, 2) {
+		got[result.DeviceID] = result
+	}
+
+	if len(got) != len(ids) {
+		t.Fatalf("received %d results; want %d", len(got), len(ids))
+	}
+	for _, id := range ids {
+		result := got[id]
+		if id == "BAD" {
+			if result.Err == nil {
+				t.Error("result for BAD has no error")
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("result for %s has error: %v", id, result.Err)
+		} else if result.Status["deviceId"] != id {
+			t.Errorf("status for %s = %v", id, result.Status)
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("max in-flight requests = %d; want <= 2", maxInFlight)
+	}
+}
+
+func TestStreamDeviceStatuses_Cancel(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(`{"power": "on"}`))
+
+	ids := make([]string, 50)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("D%d", i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := client.StreamDeviceStatuses(ctx, ids, 4)
+
+	// Read one result, then stop reading and cancel
+	<-results
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for range results {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("results channel was not closed after cancellation")
+	}
+}
@@ -0,0 +1,129 @@
+package switchbot
+
+import (
+	"slices"
+	"strings"
+)
+
+// DeviceType is the deviceType of a physical device, or the remoteType of a virtual IR remote.
+// The DeviceType* constants are untyped, so they can be used both as DeviceType and as string.
+type DeviceType string
+
+// Device types reported in the deviceType field of the device list and device status.
+const (
+	DeviceTypeBot                = "Bot"
+	DeviceTypePlug               = "Plug"
+	DeviceTypePlugMiniUS         = "Plug Mini (US)"
+	DeviceTypePlugMiniJP         = "Plug Mini (JP)"
+	DeviceTypeCurtain            = "Curtain"
+	DeviceTypeCurtain3           = "Curtain3"
+	DeviceTypeBlindTilt          = "Blind Tilt"
+	DeviceTypeColorBulb          = "Color Bulb"
+	DeviceTypeStripLight         = "Strip Light"
+	DeviceTypeCeilingLight       = "Ceiling Light"
+	DeviceTypeCeilingLightPro    = "Ceiling Light Pro"
+	DeviceTypeHumidifier         = "Humidifier"
+	DeviceTypeSmartLock          = "Smart Lock"
+	DeviceTypeSmartLockPro       = "Smart Lock Pro"
+	DeviceTypeMeter              = "Meter"
+	DeviceTypeMeterPlus          = "MeterPlus"
+	DeviceTypeMeterPro           = "MeterPro"
+	DeviceTypeOutdoorMeter       = "WoIOSensor"
+	DeviceTypeHub2               = "Hub 2"
+	DeviceTypeMotionSensor       = "Motion Sensor"
+	DeviceTypeContactSensor      = "Contact Sensor"
+	DeviceTypeWaterLeakDetector  = "Water Detector"
+	DeviceTypeMeterProCO2        = "MeterPro(CO2)"
+	DeviceTypeCO2Meter           = "CO2 Meter"
+	DeviceTypeRobotVacuumS1      = "Robot Vacuum Cleaner S1"
+	DeviceTypeRobotVacuumS1Plus  = "Robot Vacuum Cleaner S1 Plus"
+	DeviceTypeRobotVacuumK10Plus = "K10+"
+	DeviceTypeBatteryFan         = "Battery Circulator Fan"
+	DeviceTypeCirculatorFan      = "Circulator Fan"
+	DeviceTypeRemote             = "Remote"
+	DeviceTypeIndoorCam          = "Indoor Cam"
+	DeviceTypePanTiltCam         = "Pan/Tilt Cam"
+)
+
+// statelessDeviceTypes are device types whose status cannot be queried through the API.
+var statelessDeviceTypes = []string{
+	DeviceTypeRemote,
+	DeviceTypeIndoorCam,
+	DeviceTypePanTiltCam,
+}
+
+// IsStateless reports whether devices of deviceType have no queryable status.
+func IsStateless(deviceType string) bool {
+	return slices.Contains(statelessDeviceTypes, deviceType)
+}
+
+// RemoteType is the remoteType of a virtual infrared remote.
+type RemoteType string
+
+// Remote types documented for virtual infrared remotes. Remotes created with the app's DIY
+// mode report the same names prefixed with "DIY ", e.g. "DIY TV"; see DIYRemoteType.
+const (
+	RemoteTypeAirConditioner RemoteType = "Air Conditioner"
+	RemoteTypeTV             RemoteType = "TV"
+	RemoteTypeLight          RemoteType = "Light"
+	RemoteTypeStreamer       RemoteType = "Streamer"
+	RemoteTypeSetTopBox      RemoteType = "Set Top Box"
+	RemoteTypeDVD            RemoteType = "DVD"
+	RemoteTypeFan            RemoteType = "Fan"
+	RemoteTypeProjector      RemoteType = "Projector"
+	RemoteTypeCamera         RemoteType = "Camera"
+	RemoteTypeAirPurifier    RemoteType = "Air Purifier"
+	RemoteTypeSpeaker        RemoteType = "Speaker"
+	RemoteTypeWaterHeater    RemoteType = "Water Heater"
+	RemoteTypeRobotVacuum    RemoteType = "Robot Vacuum Cleaner"
+	RemoteTypeOthers         RemoteType = "Others"
+
+	RemoteTypeDIYLight = RemoteType(diyRemoteTypePrefix + RemoteTypeLight)
+)
+
+// diyRemoteTypePrefix marks remotes whose buttons were learned in the app's DIY mode.
+const diyRemoteTypePrefix = "DIY "
+
+// DIYRemoteType returns the DIY variant of t, e.g. "DIY TV" for RemoteTypeTV.
+func DIYRemoteType(t RemoteType) RemoteType {
+	if t.IsDIY() {
+		return t
+	}
+	return diyRemoteTypePrefix + t
+}
+
+// IsDIY reports whether the remote was created in DIY mode.
+func (t RemoteType) IsDIY() bool {
+	return strings.HasPrefix(string(t), diyRemoteTypePrefix)
+}
+
+// Base returns the remote type without the DIY prefix, e.g. RemoteTypeTV for "DIY TV".
+func (t RemoteType) Base() RemoteType {
+	return RemoteType(strings.TrimPrefix(string(t), diyRemoteTypePrefix))
+}
+
+// SupportsCustomizeOnly reports whether the remote only accepts customize commands
+// (commandType "customize" with the button name as the command): DIY remotes and "Others".
+func (t RemoteType) SupportsCustomizeOnly() bool {
+	return t.IsDIY() || t == RemoteTypeOthers
+}
+
+// String implements fmt.Stringer.
+func (t RemoteType) String() string {
+	return string(t)
+}
+
+// lightDeviceTypes are the physical device types and IR remote types that are lights.
+var lightDeviceTypes = []string{
+	DeviceTypeColorBulb,
+	DeviceTypeStripLight,
+	DeviceTypeCeilingLight,
+	DeviceTypeCeilingLightPro,
+	string(RemoteTypeLight),
+	string(RemoteTypeDIYLight),
+}
+
+// IsLight reports whether deviceType (or the remoteType of an IR remote) is a light.
+func IsLight(deviceType string) bool {
+	return slices.Contains(lightDeviceTypes, deviceType)
+}
@@ -0,0 +1,36 @@
+package switchbot
+
+import "testing"
+
+func TestRemoteType(t *testing.T) {
+	testCases := []struct {
+		remoteType    RemoteType
+		wantDIY       bool
+		wantBase      RemoteType
+		customizeOnly bool
+	}{
+		{RemoteTypeTV, false, RemoteTypeTV, false},
+		{"DIY TV", true, RemoteTypeTV, true},
+		{RemoteTypeDIYLight, true, RemoteTypeLight, true},
+		{RemoteTypeOthers, false, RemoteTypeOthers, true},
+		{"DIYTV", false, "DIYTV", false},
+	}
+	for _, tc := range testCases {
+		if got := tc.remoteType.IsDIY(); got != tc.wantDIY {
+			t.Errorf("%q.IsDIY() = %v; want %v", string(tc.remoteType), got, tc.wantDIY)
+		}
+		if got := tc.remoteType.Base(); got != tc.wantBase {
+			t.Errorf("%q.Base() = %q; want %q", string(tc.remoteType), string(got), string(tc.wantBase))
+		}
+		if got := tc.remoteType.SupportsCustomizeOnly(); got != tc.customizeOnly {
+			t.Errorf("%q.SupportsCustomizeOnly() = %v; want %v", string(tc.remoteType), got, tc.customizeOnly)
+		}
+	}
+
+	if got := DIYRemoteType(RemoteTypeAirConditioner); got != "DIY Air Conditioner" {
+		t.Errorf("DIYRemoteType(RemoteTypeAirConditioner) = %q; want %q", string(got), "DIY Air Conditioner")
+	}
+	if got := DIYRemoteType("DIY Fan"); got != "DIY Fan" {
+		t.Errorf("DIYRemoteType(\"DIY Fan\") = %q; want it unchanged", string(got))
+	}
+}
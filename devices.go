@@ -3,8 +3,13 @@ package switchbot
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 )
 
 // Device represents a generic physical device structure from the device list.
@@ -12,12 +17,69 @@ import (
 type Device map[string]interface{}
 
 // InfraredRemoteDevice represents a virtual infrared remote device from the device list.
+//
+// The SwitchBot API does not expose infrared learning: there is no command to start capturing
+// a remote's signal and no status reporting learning progress. IR remotes must be learned in the
+// SwitchBot app, after which they appear in InfraredRemoteList with the hub's HubDeviceID.
 type InfraredRemoteDevice struct {
 	DeviceID    string `json:"deviceId"`
 	DeviceName  string `json:"deviceName"`
 	RemoteType  string `json:"remoteType"`
 	HubDeviceID string `json:"hubDeviceId"`
-	_           struct{}
+	// Extra holds any fields not covered above, keyed by their JSON name, so no data is lost on decode.
+	Extra map[string]json.RawMessage `json:"-"`
+	_     struct{}
+}
+
+// Type returns RemoteType as a RemoteType, e.g. for IsDIY or Base.
+func (d InfraredRemoteDevice) Type() RemoteType {
+	return RemoteType(d.RemoteType)
+}
+
+// infraredRemoteKnownFields are the JSON keys decoded into the typed fields of InfraredRemoteDevice.
+var infraredRemoteKnownFields = []string{"deviceId", "deviceName", "remoteType", "hubDeviceId"}
+
+// UnmarshalJSON decodes the typed fields and collects any remaining fields into Extra.
+func (d *InfraredRemoteDevice) UnmarshalJSON(data []byte) error {
+	type plain InfraredRemoteDevice
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, key := range infraredRemoteKnownFields {
+		delete(fields, key)
+	}
+	if len(fields) > 0 {
+		decoded.Extra = fields
+	}
+
+	*d = InfraredRemoteDevice(decoded)
+	return nil
+}
+
+// MarshalJSON encodes the typed fields together with Extra, so decoded entries round-trip unchanged.
+func (d InfraredRemoteDevice) MarshalJSON() ([]byte, error) {
+	type plain InfraredRemoteDevice
+	typed, err := json.Marshal(plain(d))
+	if err != nil || len(d.Extra) == 0 {
+		return typed, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(typed, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range d.Extra {
+		if _, known := fields[key]; !known {
+			fields[key] = value
+		}
+	}
+	return json.Marshal(fields)
 }
 
 // GetDevicesResponse holds the structured response for the GetDevices endpoint.
@@ -27,51 +89,245 @@ type GetDevicesResponse struct {
 	_                  struct{}
 }
 
+// FindByName returns the first physical device whose deviceName matches name (case-insensitive).
+func (r *GetDevicesResponse) FindByName(name string) (Device, bool) {
+	for _, d := range r.DeviceList {
+		if deviceName, _ := d["deviceName"].(string); strings.EqualFold(deviceName, name) {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// FindAllByName returns all physical devices whose deviceName matches name (case-insensitive).
+func (r *GetDevicesResponse) FindAllByName(name string) []Device {
+	var matches []Device
+	for _, d := range r.DeviceList {
+		if deviceName, _ := d["deviceName"].(string); strings.EqualFold(deviceName, name) {
+			matches = append(matches, d)
+		}
+	}
+	return matches
+}
+
+// FindInfraredByName returns the first virtual infrared remote whose DeviceName matches name (case-insensitive).
+// The returned pointer refers to the element in InfraredRemoteList.
+func (r *GetDevicesResponse) FindInfraredByName(name string) (*InfraredRemoteDevice, bool) {
+	for i := range r.InfraredRemoteList {
+		if strings.EqualFold(r.InfraredRemoteList[i].DeviceName, name) {
+			return &r.InfraredRemoteList[i], true
+		}
+	}
+	return nil, false
+}
+
 // GetDevices retrieves the list of all physical and virtual infrared devices associated with the account.
+// An empty body is handled per EmptyBodyPolicy.
 func (c *Client) GetDevices(ctx context.Context) (*GetDevicesResponse, error) {
-	path := fmt.Sprintf("/%s/devices", apiVersion)
+	path := fmt.Sprintf("/%s/devices", c.apiVersion)
 	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err // Error already wrapped in doRequest
 	}
+	if _, err := c.checkEmptyBody(ctx, resp.Body, "device list"); err != nil {
+		return nil, err
+	}
 
 	var devicesResp GetDevicesResponse
-	if err := json.Unmarshal(resp.Body, &devicesResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal GetDevices response body: %w, body: %s", err, string(resp.Body))
+	if err := c.decodeMapBody(resp.Body, &devicesResp); err != nil {
+		return nil, fmt.Errorf("GetDevices response: %w", err)
 	}
 
 	return &devicesResp, nil
 }
 
+// DuplicateDeviceIDs returns the device IDs that appear more than once across the physical
+// and infrared remote lists, in order of first appearance.
+func (r *GetDevicesResponse) DuplicateDeviceIDs() []string {
+	seen := make(map[string]int)
+	var ids []string
+	record := func(id string) {
+		seen[id]++
+		if seen[id] == 2 {
+			ids = append(ids, id)
+		}
+	}
+	for _, d := range r.DeviceList {
+		if id, ok := d["deviceId"].(string); ok && id != "" {
+			record(id)
+		}
+	}
+	for _, ir := range r.InfraredRemoteList {
+		if ir.DeviceID != "" {
+			record(ir.DeviceID)
+		}
+	}
+	return ids
+}
+
+// PhysicalDevice is a physical device together with the hub or parent device it belongs to.
+type PhysicalDevice struct {
+	Device      Device // The device's own fields, as returned by the API
+	HubDeviceID string // The device's hubDeviceId, or the deviceId of the entry it was nested under
+	Nested      bool   // True if the device was found inside another device entry rather than at the top level
+	_           struct{}
+}
+
+// AllPhysicalDevices returns every physical device in DeviceList as a flat slice, including
+// devices nested inside another entry.
+//
+// The v1.1 API normally returns a flat deviceList in which sub-devices reference their hub via
+// hubDeviceId, and grouped devices (e.g. paired curtains) list their members by ID only in
+// fields such as curtainDevicesIds; those IDs are not expanded. If an entry instead carries
+// a field holding a device object or an array of device objects (any object with a deviceId),
+// each is surfaced individually after its parent, at any depth. A nested device without its own
+// hubDeviceId gets its parent's deviceId. Devices already listed are not repeated.
+func (r *GetDevicesResponse) AllPhysicalDevices() []PhysicalDevice {
+	var devices []PhysicalDevice
+	// A device listed both nested and at the top level keeps its top-level entry.
+	topLevel := make(map[string]bool)
+	for _, d := range r.DeviceList {
+		if id, _ := d["deviceId"].(string); id != "" {
+			topLevel[id] = true
+		}
+	}
+	visited := make(map[string]bool)
+	var walk func(d Device, parentID string, nested bool)
+	walk = func(d Device, parentID string, nested bool) {
+		id, _ := d["deviceId"].(string)
+		if id != "" {
+			if visited[id] || (nested && topLevel[id]) {
+				return
+			}
+			visited[id] = true
+		}
+		hubID, _ := d["hubDeviceId"].(string)
+		if hubID == "" {
+			hubID = parentID
+		}
+		devices = append(devices, PhysicalDevice{Device: d, HubDeviceID: hubID, Nested: nested})
+		// Visit fields in a stable order so the result does not depend on map iteration.
+		for _, key := range slices.Sorted(maps.Keys(d)) {
+			for _, child := range nestedDevices(d[key]) {
+				walk(child, id, true)
+			}
+		}
+	}
+	for _, d := range r.DeviceList {
+		walk(d, "", false)
+	}
+	return devices
+}
+
+// nestedDevices returns the device objects held by a field value: a single object with a
+// deviceId, or each such object in an array. Other values yield nil.
+func nestedDevices(value interface{}) []Device {
+	var candidates []interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		candidates = []interface{}{v}
+	case []interface{}:
+		candidates = v
+	default:
+		return nil
+	}
+	var devices []Device
+	for _, candidate := range candidates {
+		if m, ok := candidate.(map[string]interface{}); ok {
+			if id, _ := m["deviceId"].(string); id != "" {
+				devices = append(devices, Device(m))
+			}
+		}
+	}
+	return devices
+}
+
+// FindDuplicateDeviceIDs fetches the device list and returns any device IDs that appear more than once.
+// This should never happen in a healthy account, so a non-empty result points to a misconfiguration.
+func (c *Client) FindDuplicateDeviceIDs(ctx context.Context) ([]string, error) {
+	devicesResp, err := c.GetDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return devicesResp.DuplicateDeviceIDs(), nil
+}
+
+// PartitionDevices fetches the device list and splits it into devices that report status
+// (worth polling) and stateless devices, using IsStateless.
+func (c *Client) PartitionDevices(ctx context.Context) (stateful, stateless []Device, err error) {
+	devicesResp, err := c.GetDevices(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, d := range devicesResp.DeviceList {
+		deviceType, _ := d["deviceType"].(string)
+		if IsStateless(deviceType) {
+			stateless = append(stateless, d)
+		} else {
+			stateful = append(stateful, d)
+		}
+	}
+	return stateful, stateless, nil
+}
+
 // DeviceStatus represents the status of a device.
 // Use map[string]interface{} for flexibility as the structure is highly dependent on deviceType.
 type DeviceStatus map[string]interface{}
 
 // GetDeviceStatus retrieves the current status of a specific physical device.
+// An empty body returns an empty map by default; use EmptyBodyReturnError to get ErrEmptyBody instead.
 func (c *Client) GetDeviceStatus(ctx context.Context, deviceID string) (DeviceStatus, error) {
 	if deviceID == "" {
 		return nil, fmt.Errorf("deviceID cannot be empty")
 	}
-	path := fmt.Sprintf("/%s/devices/%s/status", apiVersion, deviceID)
+	path := fmt.Sprintf("/%s/devices/%s/status", c.apiVersion, deviceID)
 	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	// The API usually returns structured data or an error; an empty body is handled per EmptyBodyPolicy.
+	if empty, err := c.checkEmptyBody(ctx, resp.Body, "device status for "+deviceID); err != nil {
+		return nil, err
+	} else if empty {
+		return make(DeviceStatus), nil
+	}
+
 	var status DeviceStatus
-	// Handle potentially empty body for devices without status (though unlikely based on docs)
-	if isEmptyJSONBody(resp.Body) {
-		if err := json.Unmarshal(resp.Body, &status); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal GetDeviceStatus response body for %s: %w, body: %s", deviceID, err, string(resp.Body))
-		}
-	} else {
-		// Return an empty map if the body is empty, though the API usually returns structured data or an error.
-		status = make(DeviceStatus)
+	if err := c.decodeMapBody(resp.Body, &status); err != nil {
+		return nil, fmt.Errorf("GetDeviceStatus response for %s: %w", deviceID, err)
 	}
 
 	return status, nil
 }
 
+// ErrInvalidCommandType is returned when a commandType other than "command" or "customize" is given.
+var ErrInvalidCommandType = errors.New("invalid command type")
+
+// CommandType is the commandType of a device command.
+type CommandType string
+
+const (
+	CommandTypeStandard  CommandType = "command"   // Built-in commands such as turnOn; the default
+	CommandTypeCustomize CommandType = "customize" // User-defined buttons of IR remotes, named by the command
+)
+
+// String implements fmt.Stringer.
+func (t CommandType) String() string {
+	return string(t)
+}
+
+// parseCommandType validates a commandType string, mapping "" to CommandTypeStandard.
+func parseCommandType(commandType string) (CommandType, error) {
+	switch CommandType(commandType) {
+	case "", CommandTypeStandard:
+		return CommandTypeStandard, nil
+	case CommandTypeCustomize:
+		return CommandTypeCustomize, nil
+	}
+	return "", fmt.Errorf("%w: %q must be %q or %q", ErrInvalidCommandType, commandType, string(CommandTypeStandard), string(CommandTypeCustomize))
+}
+
 // CommandRequest represents the JSON body for sending a command to a device.
 type CommandRequest struct {
 	Command     string      `json:"command"`
@@ -80,53 +336,106 @@ type CommandRequest struct {
 	_           struct{}
 }
 
+// String returns a one-line summary of the command for logging,
+// e.g. `setBrightness (command) parameter=50`. Structured parameters are rendered as compact JSON.
+func (r CommandRequest) String() string {
+	var parameter string
+	switch p := r.Parameter.(type) {
+	case nil:
+		parameter = "default"
+	case string:
+		parameter = p
+	default:
+		if b, err := json.Marshal(p); err == nil {
+			parameter = string(b)
+		} else {
+			parameter = fmt.Sprintf("%v", p)
+		}
+	}
+	commandType := r.CommandType
+	if commandType == "" {
+		commandType = "command"
+	}
+	return fmt.Sprintf("%s (%s) parameter=%s", r.Command, commandType, parameter)
+}
+
 // CommandResponse represents the response body after sending a command.
 // Often empty ({}), but can contain fields like "commandId" for asynchronous operations (e.g., Keypad).
 // Use map[string]interface{} for flexibility.
 type CommandResponse map[string]interface{}
 
 // SendDeviceCommand sends a control command to a specific device (physical or virtual IR).
-// parameter: Use "default" for simple commands, or a map/struct for complex ones (e.g., setAll, setMode).
-// commandType: Use "command" (default) for standard commands, "customize" for IR custom buttons.
+// parameter: Use "default" for simple commands, or a map/struct for complex ones (e.g., setAll, setMode);
+// a CommandParameter is sent as its plain value.
+// commandType: Use "command" (default) for standard commands, "customize" for IR custom buttons;
+// any other value returns ErrInvalidCommandType. See also SendDeviceCommandWithType.
 func (c *Client) SendDeviceCommand(ctx context.Context, deviceID string, command string, parameter interface{}, commandType string) (CommandResponse, error) {
+	cmdResp, _, err := c.sendDeviceCommand(ctx, deviceID, command, parameter, commandType)
+	return cmdResp, err
+}
+
+// SendDeviceCommandWithType is SendDeviceCommand with a typed parameter and commandType.
+// A nil parameter is sent as DefaultParameter.
+func (c *Client) SendDeviceCommandWithType(ctx context.Context, deviceID string, command string, parameter CommandParameter, commandType CommandType) (CommandResponse, error) {
+	var value interface{}
+	if parameter != nil {
+		value = parameter.parameterValue()
+	}
+	return c.SendDeviceCommand(ctx, deviceID, command, value, string(commandType))
+}
+
+// SendDeviceCommandTimed is like SendDeviceCommand but also reports the HTTP round-trip duration
+// of the API call, which helps detect slow devices or hubs. The duration is reported even on error
+// when the request reached the network, and is zero if it could not be sent.
+func (c *Client) SendDeviceCommandTimed(ctx context.Context, deviceID string, command string, parameter interface{}, commandType string) (CommandResponse, time.Duration, error) {
+	return c.sendDeviceCommand(ctx, deviceID, command, parameter, commandType)
+}
+
+func (c *Client) sendDeviceCommand(ctx context.Context, deviceID string, command string, parameter interface{}, commandType string) (CommandResponse, time.Duration, error) {
 	if deviceID == "" {
-		return nil, fmt.Errorf("deviceID cannot be empty")
+		return nil, 0, fmt.Errorf("deviceID cannot be empty")
 	}
 	if command == "" {
-		return nil, fmt.Errorf("command cannot be empty")
+		return nil, 0, fmt.Errorf("command cannot be empty")
 	}
 
 	// Set defaults if not provided
-	effectiveParameter := parameter
+	effectiveParameter := plainParameter(parameter)
 	if effectiveParameter == nil {
 		effectiveParameter = "default"
 	}
-	effectiveCommandType := commandType
-	if effectiveCommandType == "" {
-		effectiveCommandType = "command" // Default for most API commands
+	effectiveCommandType, err := parseCommandType(commandType) // "" defaults to "command"
+	if err != nil {
+		return nil, 0, err
 	}
 
 	reqBody := CommandRequest{
 		Command:     command,
 		Parameter:   effectiveParameter,
-		CommandType: effectiveCommandType,
+		CommandType: string(effectiveCommandType),
 	}
 
-	path := fmt.Sprintf("/%s/devices/%s/commands", apiVersion, deviceID)
-	resp, err := c.doRequest(ctx, http.MethodPost, path, reqBody)
+	c.loggerFor(ctx).DebugContext(ctx, "sending device command", "deviceId", deviceID, "command", reqBody.String())
+
+	path := fmt.Sprintf("/%s/devices/%s/commands", c.apiVersion, deviceID)
+	resp, elapsed, err := c.doRequestTimed(ctx, http.MethodPost, path, reqBody)
 	if err != nil {
-		return nil, err
+		return nil, elapsed, err
 	}
 
+	cmdResp, err := c.decodeCommandResponse(resp.Body, deviceID)
+	return cmdResp, elapsed, err
+}
+
+// decodeCommandResponse unmarshals a command response body. Most commands succeed with an empty
+// body, so an empty body always yields an empty map regardless of EmptyBodyPolicy.
+func (c *Client) decodeCommandResponse(body json.RawMessage, deviceID string) (CommandResponse, error) {
+	if isEmptyJSONBody(body) {
+		return make(CommandResponse), nil
+	}
 	var cmdResp CommandResponse
-	// Handle potentially empty body for successful commands
-	if isEmptyJSONBody(resp.Body) {
-		if err := json.Unmarshal(resp.Body, &cmdResp); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal SendDeviceCommand response body for %s: %w, body: %s", deviceID, err, string(resp.Body))
-		}
-	} else {
-		cmdResp = make(CommandResponse) // Return empty map for empty body
+	if err := decodeBody(body, &cmdResp); err != nil {
+		return nil, fmt.Errorf("command response for %s: %w", deviceID, err)
 	}
-
 	return cmdResp, nil
 }
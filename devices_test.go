@@ -0,0 +1,287 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetDevicesResponse_FindByName(t *testing.T) {
+	resp := &GetDevicesResponse{
+		DeviceList: []Device{
+			{"deviceId": "D1", "deviceName": "Living Room Light", "deviceType": "Color Bulb"},
+			{"deviceId": "D2", "deviceName": "Bedroom Curtain", "deviceType": "Curtain"},
+			{"deviceId": "D3", "deviceName": "living room light", "deviceType": "Strip Light"},
+		},
+		InfraredRemoteList: []InfraredRemoteDevice{
+			{DeviceID: "IR1", DeviceName: "Living Room TV", RemoteType: "TV"},
+		},
+	}
+
+	t.Run("FindByName", func(t *testing.T) {
+		d, ok := resp.FindByName("LIVING ROOM LIGHT")
+		if !ok {
+			t.Fatal("FindByName() found no device")
+		}
+		if d["deviceId"] != "D1" {
+			t.Errorf("FindByName() returned %v; want first match D1", d["deviceId"])
+		}
+		if _, ok := resp.FindByName("Kitchen"); ok {
+			t.Error("FindByName() found a device for an unknown name")
+		}
+	})
+
+	t.Run("FindAllByName", func(t *testing.T) {
+		matches := resp.FindAllByName("Living Room Light")
+		if len(matches) != 2 {
+			t.Fatalf("FindAllByName() returned %d devices; want 2", len(matches))
+		}
+		if matches[0]["deviceId"] != "D1" || matches[1]["deviceId"] != "D3" {
+			t.Errorf("FindAllByName() returned %v, %v; want D1, D3", matches[0]["deviceId"], matches[1]["deviceId"])
+		}
+	})
+
+	t.Run("FindInfraredByName", func(t *testing.T) {
+		ir, ok := resp.FindInfraredByName("living room tv")
+		if !ok {
+			t.Fatal("FindInfraredByName() found no remote")
+		}
+		if ir.DeviceID != "IR1" {
+			t.Errorf("FindInfraredByName() returned %q; want IR1", ir.DeviceID)
+		}
+		if _, ok := resp.FindInfraredByName("Bedroom TV"); ok {
+			t.Error("FindInfraredByName() found a remote for an unknown name")
+		}
+	})
+}
+
+func TestInfraredRemoteDevice_Extra(t *testing.T) {
+	data := []byte(`{"deviceId": "IR1", "deviceName": "Bedroom AC", "remoteType": "DIY Air Conditioner", "hubDeviceId": "H1", "brand": "Daikin", "learned": true}`)
+
+	var d InfraredRemoteDevice
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	if d.DeviceID != "IR1" || d.DeviceName != "Bedroom AC" || d.RemoteType != "DIY Air Conditioner" || d.HubDeviceID != "H1" {
+		t.Errorf("typed fields not decoded: %+v", d)
+	}
+	if !d.Type().IsDIY() || d.Type().Base() != RemoteTypeAirConditioner {
+		t.Errorf("Type() = %q; want a DIY Air Conditioner", d.Type())
+	}
+	if len(d.Extra) != 2 {
+		t.Fatalf("Extra has %d entries; want 2: %v", len(d.Extra), d.Extra)
+	}
+	if string(d.Extra["brand"]) != `"Daikin"` {
+		t.Errorf("Extra[brand] = %s; want %q", d.Extra["brand"], `"Daikin"`)
+	}
+	if _, ok := d.Extra["deviceId"]; ok {
+		t.Error("Extra contains typed field deviceId")
+	}
+
+	// Round trip keeps the extra fields
+	encoded, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	var roundTrip map[string]interface{}
+	if err := json.Unmarshal(encoded, &roundTrip); err != nil {
+		t.Fatalf("Unmarshal of encoded device returned error: %v", err)
+	}
+	if roundTrip["brand"] != "Daikin" || roundTrip["learned"] != true || roundTrip["deviceId"] != "IR1" {
+		t.Errorf("round trip lost fields: %s", encoded)
+	}
+
+	// Entries without unknown fields leave Extra nil
+	var plain InfraredRemoteDevice
+	if err := json.Unmarshal([]byte(`{"deviceId": "IR2", "deviceName": "TV", "remoteType": "TV", "hubDeviceId": "H1"}`), &plain); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if plain.Extra != nil {
+		t.Errorf("Extra = %v; want nil", plain.Extra)
+	}
+}
+
+func TestSendDeviceCommandTimed(t *testing.T) {
+	const delay = 20 * time.Millisecond
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 100, "message": "success", "body": {}}`)
+	}
+	client, _ := setupMockServer(t, handler)
+
+	resp, elapsed, err := client.SendDeviceCommandTimed(context.Background(), "B1", "press", nil, "")
+	if err != nil {
+		t.Fatalf("SendDeviceCommandTimed() returned error: %v", err)
+	}
+	if resp == nil {
+		t.Error("SendDeviceCommandTimed() returned nil response")
+	}
+	if elapsed < delay {
+		t.Errorf("elapsed = %v; want at least the server delay %v", elapsed, delay)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("elapsed = %v; implausibly long", elapsed)
+	}
+
+	// Validation errors happen before any request is sent
+	_, elapsed, err = client.SendDeviceCommandTimed(context.Background(), "", "press", nil, "")
+	if err == nil {
+		t.Error("SendDeviceCommandTimed() with empty deviceID did not return an error")
+	}
+	if elapsed != 0 {
+		t.Errorf("elapsed = %v for unsent request; want 0", elapsed)
+	}
+}
+
+func TestFindDuplicateDeviceIDs(t *testing.T) {
+	body := `{"deviceList": [{"deviceId": "D1"}, {"deviceId": "D2"}, {"deviceId": "D1"}], "infraredRemoteList": [{"deviceId": "IR1"}, {"deviceId": "D2"}, {"deviceId": "IR2"}]}`
+	client, _ := setupMockServer(t, statusHandler(body))
+
+	ids, err := client.FindDuplicateDeviceIDs(context.Background())
+	if err != nil {
+		t.Fatalf("FindDuplicateDeviceIDs() returned error: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "D1" || ids[1] != "D2" {
+		t.Errorf("FindDuplicateDeviceIDs() = %v; want [D1 D2]", ids)
+	}
+
+	unique := &GetDevicesResponse{DeviceList: []Device{{"deviceId": "D1"}}, InfraredRemoteList: []InfraredRemoteDevice{{DeviceID: "IR1"}}}
+	if dups := unique.DuplicateDeviceIDs(); len(dups) != 0 {
+		t.Errorf("DuplicateDeviceIDs() = %v; want none", dups)
+	}
+}
+
+func TestCommandRequestString(t *testing.T) {
+	testCases := []struct {
+		name string
+		req  CommandRequest
+		want string
+	}{
+		{"Default", CommandRequest{Command: "turnOn", CommandType: "command", Parameter: "default"}, "turnOn (command) parameter=default"},
+		{"NilParameter", CommandRequest{Command: "press"}, "press (command) parameter=default"},
+		{"Numeric", CommandRequest{Command: "setBrightness", CommandType: "command", Parameter: 50}, "setBrightness (command) parameter=50"},
+		{"Structured", CommandRequest{Command: "createKey", CommandType: "command", Parameter: map[string]string{"name": "guest", "type": "permanent"}}, `createKey (command) parameter={"name":"guest","type":"permanent"}`},
+		{"Customize", CommandRequest{Command: "MyButton", CommandType: "customize", Parameter: "default"}, "MyButton (customize) parameter=default"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.req.String(); got != tc.want {
+				t.Errorf("String() = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSendDeviceCommandLogsCommand(t *testing.T) {
+	var got []capturedCommand
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, _ := setupMockServer(t, commandCaptureHandler(t, &got), WithLogger(logger))
+
+	if _, err := client.SendDeviceCommand(context.Background(), "B1", "setBrightness", 50, ""); err != nil {
+		t.Fatalf("SendDeviceCommand() returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `command="setBrightness (command) parameter=50"`) {
+		t.Errorf("log output = %q; want command summary", buf.String())
+	}
+}
+
+func TestSendDeviceCommandCommandType(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+	if _, err := client.SendDeviceCommandWithType(context.Background(), "IR1", "MyButton", nil, CommandTypeCustomize); err != nil {
+		t.Fatalf("SendDeviceCommandWithType() returned error: %v", err)
+	}
+	if _, err := client.SendDeviceCommand(context.Background(), "B1", "press", nil, "command"); err != nil {
+		t.Fatalf("SendDeviceCommand() returned error: %v", err)
+	}
+	if len(got) != 2 || got[0].CommandType != "customize" || got[1].CommandType != "command" {
+		t.Fatalf("received %+v; want customize then command", got)
+	}
+
+	_, err := client.SendDeviceCommand(context.Background(), "B1", "press", nil, "Customize")
+	if !errors.Is(err, ErrInvalidCommandType) {
+		t.Errorf("SendDeviceCommand() with commandType Customize error = %v; want ErrInvalidCommandType", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("received %d requests; want the invalid command not to be sent", len(got))
+	}
+}
+
+func TestPartitionDevices(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(`{
+		"deviceList": [
+			{"deviceId": "B1", "deviceName": "Bot", "deviceType": "Bot"},
+			{"deviceId": "R1", "deviceName": "Remote", "deviceType": "Remote"},
+			{"deviceId": "M1", "deviceName": "Meter", "deviceType": "Meter"},
+			{"deviceId": "C1", "deviceName": "Cam", "deviceType": "Indoor Cam"}
+		],
+		"infraredRemoteList": [{"deviceId": "IR1", "deviceName": "TV", "remoteType": "TV"}]
+	}`))
+
+	stateful, stateless, err := client.PartitionDevices(context.Background())
+	if err != nil {
+		t.Fatalf("PartitionDevices() returned error: %v", err)
+	}
+	ids := func(devices []Device) []string {
+		var out []string
+		for _, d := range devices {
+			out = append(out, d["deviceId"].(string))
+		}
+		return out
+	}
+	if got := fmt.Sprint(ids(stateful)); got != "[B1 M1]" {
+		t.Errorf("stateful = %s; want [B1 M1]", got)
+	}
+	if got := fmt.Sprint(ids(stateless)); got != "[R1 C1]" {
+		t.Errorf("stateless = %s; want [R1 C1]", got)
+	}
+}
+
+func TestGetDevicesResponse_AllPhysicalDevices(t *testing.T) {
+	body := `{"deviceList": [
+		{"deviceId": "H1", "deviceType": "Hub 2", "hubDeviceId": "000000000000",
+		 "subDevices": [
+			{"deviceId": "C1", "deviceType": "Curtain", "group": {"deviceId": "C2", "deviceType": "Curtain"}},
+			{"deviceId": "M1", "deviceType": "Meter"},
+			{"deviceId": "B1", "deviceType": "Bot", "hubDeviceId": "H1"}
+		 ]},
+		{"deviceId": "B1", "deviceType": "Bot", "hubDeviceId": "H1"},
+		{"deviceId": "C3", "deviceType": "Curtain", "hubDeviceId": "H1", "curtainDevicesIds": ["C3", "C4"]}
+	]}`
+	var resp GetDevicesResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("json.Unmarshal() returned error: %v", err)
+	}
+
+	got := resp.AllPhysicalDevices()
+	want := []struct {
+		id, hub string
+		nested  bool
+	}{
+		{"H1", "000000000000", false},
+		{"C1", "H1", true},
+		{"C2", "C1", true},
+		{"M1", "H1", true},
+		{"B1", "H1", false},
+		{"C3", "H1", false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("AllPhysicalDevices() returned %d devices; want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Device["deviceId"] != w.id || got[i].HubDeviceID != w.hub || got[i].Nested != w.nested {
+			t.Errorf("device %d = {%v %q %v}; want {%s %q %v}", i, got[i].Device["deviceId"], got[i].HubDeviceID, got[i].Nested, w.id, w.hub, w.nested)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const redactedValue = "[REDACTED]"
+
+// urlPattern matches URLs in error messages, which may carry a proxy base path or device IDs.
+var urlPattern = regexp.MustCompile(`https?://[^\s"']+`)
+
+// RateLimitInfo holds the rate-limit information reported by the most recent API response.
+// SwitchBot does not document these headers, so fields are only populated when present.
+type RateLimitInfo struct {
+	Limit      int       `json:"limit,omitempty"`
+	Remaining  int       `json:"remaining,omitempty"`
+	Reset      time.Time `json:"reset,omitempty"`
+	ObservedAt time.Time `json:"observedAt"`
+	_          struct{}
+}
+
+// recordResponseMeta stores rate-limit and server clock information from the response headers.
+func (c *Client) recordResponseMeta(header http.Header) {
+	now := time.Now()
+
+	var rateLimit *RateLimitInfo
+	limit, limitErr := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	remaining, remainingErr := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if limitErr == nil || remainingErr == nil {
+		rateLimit = &RateLimitInfo{Limit: limit, Remaining: remaining, ObservedAt: now}
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			rateLimit.Reset = time.Unix(reset, 0)
+		}
+	}
+
+	serverDate, dateErr := http.ParseTime(header.Get("Date"))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if rateLimit != nil {
+		c.lastRateLimit = rateLimit
+	}
+	if dateErr == nil {
+		c.lastServerDate = serverDate
+		c.lastLocalDate = now
+	}
+}
+
+// LastRateLimit returns a copy of the most recently observed rate-limit information, or nil if none has been seen.
+func (c *Client) LastRateLimit() *RateLimitInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lastRateLimit == nil {
+		return nil
+	}
+	info := *c.lastRateLimit
+	return &info
+}
+
+// DiagnosticsConfig is the redacted client configuration included in a diagnostic report.
+type DiagnosticsConfig struct {
+	BaseURL             string `json:"baseUrl"`
+	APIVersion          string `json:"apiVersion"`
+	Token               string `json:"token"`
+	Secret              string `json:"secret"`
+	HTTPClientTimeout   string `json:"httpClientTimeout"`
+	CustomHTTPClient    bool   `json:"customHttpClient"`
+	CredentialsProvider bool   `json:"credentialsProvider"`
+	_                   struct{}
+}
+
+// ConnectivityCheck records the outcome of a test request made while building a diagnostic report.
+type ConnectivityCheck struct {
+	OK            bool   `json:"ok"`
+	LatencyMillis int64  `json:"latencyMs"`
+	Error         string `json:"error,omitempty"` // URLs are redacted
+	_             struct{}
+}
+
+// Diagnostics is a shareable snapshot of the client state, suitable for attaching to bug reports.
+// Credentials are always redacted.
+type Diagnostics struct {
+	GeneratedAt         time.Time         `json:"generatedAt"`
+	Config              DiagnosticsConfig `json:"config"`
+	Connectivity        ConnectivityCheck `json:"connectivity"`
+	DeviceCount         int               `json:"deviceCount"`
+	InfraredRemoteCount int               `json:"infraredRemoteCount"`
+	RateLimit           *RateLimitInfo    `json:"rateLimit,omitempty"`
+	ClockSkewMillis     *int64            `json:"clockSkewMs,omitempty"` // Server time minus local time, when the server sent a Date header
+	_                   struct{}
+}
+
+// DiagnosticReport collects a redacted diagnostic snapshot of the client.
+// A failed connectivity check is recorded in the report rather than returned as an error.
+func (c *Client) DiagnosticReport(ctx context.Context) (*Diagnostics, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &Diagnostics{
+		GeneratedAt: time.Now(),
+		Config: DiagnosticsConfig{
+			BaseURL:             c.baseURL.Redacted(),
+			APIVersion:          c.apiVersion,
+			Token:               redactCredential(c.token),
+			Secret:              redactCredential(c.secret),
+			HTTPClientTimeout:   c.httpClient.Timeout.String(),
+			CustomHTTPClient:    c.httpClient != http.DefaultClient,
+			CredentialsProvider: c.credentialsProvider != nil,
+		},
+	}
+
+	start := time.Now()
+	devicesResp, err := c.GetDevices(ctx)
+	report.Connectivity.LatencyMillis = time.Since(start).Milliseconds()
+	if err != nil {
+		report.Connectivity.Error = urlPattern.ReplaceAllString(err.Error(), redactedValue)
+	} else {
+		report.Connectivity.OK = true
+		report.DeviceCount = len(devicesResp.DeviceList)
+		report.InfraredRemoteCount = len(devicesResp.InfraredRemoteList)
+	}
+
+	report.RateLimit = c.LastRateLimit()
+
+	c.mu.Lock()
+	if !c.lastServerDate.IsZero() {
+		skew := c.lastServerDate.Sub(c.lastLocalDate).Milliseconds()
+		report.ClockSkewMillis = &skew
+	}
+	c.mu.Unlock()
+
+	return report, nil
+}
+
+// redactCredential hides a credential value while still indicating whether it was set.
+func redactCredential(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
@@ -0,0 +1,113 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiagnosticReport(t *testing.T) {
+	serverDate := time.Now().Add(-90 * time.Second).UTC()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverDate.Format(http.TimeFormat))
+		w.Header().Set("X-RateLimit-Limit", "10000")
+		w.Header().Set("X-RateLimit-Remaining", "9876")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 100, "message": "success", "body": {"deviceList": [{"deviceId": "D1"}, {"deviceId": "D2"}], "infraredRemoteList": [{"deviceId": "IR1"}]}}`)
+	}
+
+	client, _ := setupMockServer(t, handler)
+
+	report, err := client.DiagnosticReport(context.Background())
+	if err != nil {
+		t.Fatalf("DiagnosticReport() returned error: %v", err)
+	}
+
+	t.Run("Populated", func(t *testing.T) {
+		if !report.Connectivity.OK {
+			t.Errorf("Connectivity.OK = false; error = %q", report.Connectivity.Error)
+		}
+		if report.DeviceCount != 2 {
+			t.Errorf("DeviceCount = %d; want 2", report.DeviceCount)
+		}
+		if report.InfraredRemoteCount != 1 {
+			t.Errorf("InfraredRemoteCount = %d; want 1", report.InfraredRemoteCount)
+		}
+		if report.RateLimit == nil {
+			t.Fatal("RateLimit is nil; want populated from headers")
+		}
+		if report.RateLimit.Limit != 10000 || report.RateLimit.Remaining != 9876 {
+			t.Errorf("RateLimit = %+v; want Limit=10000 Remaining=9876", *report.RateLimit)
+		}
+		if report.ClockSkewMillis == nil {
+			t.Fatal("ClockSkewMillis is nil; want populated from Date header")
+		}
+		// Date header has second resolution, so allow some slack around -90s.
+		if skew := *report.ClockSkewMillis; skew > -88000 || skew < -92000 {
+			t.Errorf("ClockSkewMillis = %d; want about -90000", skew)
+		}
+	})
+
+	t.Run("Redacted", func(t *testing.T) {
+		if report.Config.Token != redactedValue {
+			t.Errorf("Config.Token = %q; want %q", report.Config.Token, redactedValue)
+		}
+		if report.Config.Secret != redactedValue {
+			t.Errorf("Config.Secret = %q; want %q", report.Config.Secret, redactedValue)
+		}
+		b, err := json.Marshal(report)
+		if err != nil {
+			t.Fatalf("Failed to marshal report: %v", err)
+		}
+		if strings.Contains(string(b), client.token) || strings.Contains(string(b), client.secret) {
+			t.Errorf("Serialized report leaks credentials: %s", string(b))
+		}
+	})
+}
+
+func TestDiagnosticReport_ConnectivityFailure(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 190, "message": "internal error", "body": {}}`)
+	}
+
+	client, _ := setupMockServer(t, handler)
+
+	report, err := client.DiagnosticReport(context.Background())
+	if err != nil {
+		t.Fatalf("DiagnosticReport() returned error: %v", err)
+	}
+	if report.Connectivity.OK {
+		t.Error("Connectivity.OK = true; want false")
+	}
+	if report.Connectivity.Error == "" {
+		t.Error("Connectivity.Error is empty; want API error message")
+	}
+	if report.RateLimit != nil {
+		t.Errorf("RateLimit = %+v; want nil without headers", *report.RateLimit)
+	}
+}
+
+func TestDiagnosticReport_RedactsURLInError(t *testing.T) {
+	// Nothing listens on port 1, so the request fails with an error naming the URL.
+	client, err := NewClient("token", "secret", WithBaseURL("http://127.0.0.1:1/private-proxy"))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	report, err := client.DiagnosticReport(context.Background())
+	if err != nil {
+		t.Fatalf("DiagnosticReport() returned error: %v", err)
+	}
+	if report.Connectivity.Error == "" {
+		t.Fatal("Connectivity.Error is empty; want the connection error")
+	}
+	if strings.Contains(report.Connectivity.Error, "private-proxy") || strings.Contains(report.Connectivity.Error, "/devices") {
+		t.Errorf("Connectivity.Error = %q; want the URL redacted", report.Connectivity.Error)
+	}
+}
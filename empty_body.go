@@ -0,0 +1,76 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyBody is returned, wrapped, when a successful response has an empty body ({}, null or
+// nothing) where data was expected: always by the typed status getters (e.g. GetFanStatus), and
+// by GetDevices and GetDeviceStatus under EmptyBodyReturnError.
+var ErrEmptyBody = errors.New("empty response body")
+
+// EmptyBodyPolicy controls what GetDevices and GetDeviceStatus return when the API reports
+// success (statusCode 100) but the body is empty, {} or null. It does not apply to command
+// responses, whose body is normally empty.
+type EmptyBodyPolicy int
+
+const (
+	// EmptyBodyReturnEmpty returns an empty, non-nil map (an empty GetDevicesResponse for
+	// GetDevices), which cannot be told apart from a device with no status fields. This is the default.
+	EmptyBodyReturnEmpty EmptyBodyPolicy = iota
+	// EmptyBodyReturnError returns an error wrapping ErrEmptyBody.
+	EmptyBodyReturnError
+)
+
+// String implements fmt.Stringer.
+func (p EmptyBodyPolicy) String() string {
+	switch p {
+	case EmptyBodyReturnEmpty:
+		return "ReturnEmpty"
+	case EmptyBodyReturnError:
+		return "ReturnError"
+	}
+	return fmt.Sprintf("EmptyBodyPolicy(%d)", int(p))
+}
+
+// WithEmptyBodyPolicy sets the client-wide EmptyBodyPolicy.
+// It can be overridden per call with ContextWithEmptyBodyPolicy.
+func WithEmptyBodyPolicy(policy EmptyBodyPolicy) ClientOption {
+	return func(c *Client) error {
+		if policy != EmptyBodyReturnEmpty && policy != EmptyBodyReturnError {
+			return fmt.Errorf("unknown EmptyBodyPolicy: %s", policy)
+		}
+		c.emptyBodyPolicy = policy
+		return nil
+	}
+}
+
+type emptyBodyPolicyKey struct{}
+
+// ContextWithEmptyBodyPolicy returns a copy of ctx that overrides the client's EmptyBodyPolicy
+// for calls made with it.
+func ContextWithEmptyBodyPolicy(ctx context.Context, policy EmptyBodyPolicy) context.Context {
+	return context.WithValue(ctx, emptyBodyPolicyKey{}, policy)
+}
+
+// emptyBodyPolicyFor returns the policy in effect for ctx.
+func (c *Client) emptyBodyPolicyFor(ctx context.Context) EmptyBodyPolicy {
+	if policy, ok := ctx.Value(emptyBodyPolicyKey{}).(EmptyBodyPolicy); ok {
+		return policy
+	}
+	return c.emptyBodyPolicy
+}
+
+// checkEmptyBody reports whether body is empty and, if so, whether the policy turns that into an error.
+func (c *Client) checkEmptyBody(ctx context.Context, body json.RawMessage, what string) (bool, error) {
+	if !isEmptyJSONBody(body) {
+		return false, nil
+	}
+	if c.emptyBodyPolicyFor(ctx) == EmptyBodyReturnError {
+		return true, fmt.Errorf("%w: %s", ErrEmptyBody, what)
+	}
+	return true, nil
+}
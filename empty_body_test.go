@@ -0,0 +1,86 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEmptyBodyPolicy(t *testing.T) {
+	for _, body := range []string{`{}`, `null`} {
+		t.Run("ReturnEmpty/"+body, func(t *testing.T) {
+			client, _ := setupMockServer(t, statusHandler(body))
+
+			status, err := client.GetDeviceStatus(context.Background(), "D1")
+			if err != nil {
+				t.Fatalf("GetDeviceStatus() returned error: %v", err)
+			}
+			if status == nil || len(status) != 0 {
+				t.Errorf("GetDeviceStatus() = %v; want empty non-nil map", status)
+			}
+			resp, err := client.SendDeviceCommand(context.Background(), "D1", "turnOn", nil, "")
+			if err != nil {
+				t.Fatalf("SendDeviceCommand() returned error: %v", err)
+			}
+			if resp == nil || len(resp) != 0 {
+				t.Errorf("SendDeviceCommand() = %v; want empty non-nil map", resp)
+			}
+		})
+
+		t.Run("ReturnError/"+body, func(t *testing.T) {
+			client, _ := setupMockServer(t, statusHandler(body), WithEmptyBodyPolicy(EmptyBodyReturnError))
+
+			if _, err := client.GetDeviceStatus(context.Background(), "D1"); !errors.Is(err, ErrEmptyBody) {
+				t.Errorf("GetDeviceStatus() error = %v; want ErrEmptyBody", err)
+			}
+			// Command responses are normally empty and never subject to the policy.
+			resp, err := client.SendDeviceCommand(context.Background(), "D1", "turnOn", nil, "")
+			if err != nil {
+				t.Errorf("SendDeviceCommand() returned error: %v", err)
+			} else if resp == nil || len(resp) != 0 {
+				t.Errorf("SendDeviceCommand() = %v; want empty non-nil map", resp)
+			}
+			if _, err := client.GetDevices(context.Background()); !errors.Is(err, ErrEmptyBody) {
+				t.Errorf("GetDevices() error = %v; want ErrEmptyBody", err)
+			}
+		})
+
+		t.Run("TypedStatus/"+body, func(t *testing.T) {
+			client, _ := setupMockServer(t, statusHandler(body))
+
+			// Typed getters need a deviceType, so they fail under either policy.
+			if _, err := client.GetFanStatus(context.Background(), "D1"); !errors.Is(err, ErrEmptyBody) {
+				t.Errorf("GetFanStatus() error = %v; want ErrEmptyBody", err)
+			}
+		})
+	}
+
+	t.Run("PerCallOverride", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(`{}`))
+
+		ctx := ContextWithEmptyBodyPolicy(context.Background(), EmptyBodyReturnError)
+		if _, err := client.GetDeviceStatus(ctx, "D1"); !errors.Is(err, ErrEmptyBody) {
+			t.Errorf("GetDeviceStatus() error = %v; want ErrEmptyBody", err)
+		}
+		if _, err := client.GetDeviceStatus(context.Background(), "D1"); err != nil {
+			t.Errorf("GetDeviceStatus() without override returned error: %v", err)
+		}
+	})
+
+	t.Run("NonEmptyBodyUnaffected", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(`{"deviceId": "D1", "power": "on"}`), WithEmptyBodyPolicy(EmptyBodyReturnError))
+		status, err := client.GetDeviceStatus(context.Background(), "D1")
+		if err != nil {
+			t.Fatalf("GetDeviceStatus() returned error: %v", err)
+		}
+		if status["power"] != "on" {
+			t.Errorf("power = %v; want on", status["power"])
+		}
+	})
+
+	t.Run("UnknownPolicy", func(t *testing.T) {
+		if _, err := NewClient("token", "secret", WithEmptyBodyPolicy(EmptyBodyPolicy(7))); err == nil {
+			t.Error("NewClient() with unknown policy returned nil error")
+		}
+	})
+}
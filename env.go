@@ -0,0 +1,58 @@
+package switchbot
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Environment variables read by NewClientFromEnv unless overridden with WithEnvNames.
+const (
+	DefaultTokenEnv  = "SWITCHBOT_TOKEN"
+	DefaultSecretEnv = "SWITCHBOT_SECRET"
+)
+
+// WithEnvNames sets the environment variables NewClientFromEnv reads the token and secret from,
+// e.g. for multiple accounts. It has no effect on NewClient.
+func WithEnvNames(tokenEnv, secretEnv string) ClientOption {
+	return func(c *Client) error {
+		if tokenEnv == "" || secretEnv == "" {
+			return fmt.Errorf("environment variable names cannot be empty")
+		}
+		c.build.tokenEnv = tokenEnv
+		c.build.secretEnv = secretEnv
+		return nil
+	}
+}
+
+// NewClientFromEnv is NewClient with the token and secret read from the SWITCHBOT_TOKEN and
+// SWITCHBOT_SECRET environment variables, or those set with WithEnvNames.
+// An unset or empty variable returns an error naming it, unless WithCredentialsProvider is set,
+// in which case the variables are not read.
+func NewClientFromEnv(options ...ClientOption) (*Client, error) {
+	client, build, err := newClient("", "", options)
+	if err != nil {
+		return nil, err
+	}
+	if client.credentialsProvider != nil {
+		return client, nil
+	}
+
+	client.token = os.Getenv(build.tokenEnv)
+	client.secret = os.Getenv(build.secretEnv)
+	var missing []string
+	if client.token == "" {
+		missing = append(missing, build.tokenEnv)
+	}
+	if client.secret == "" {
+		missing = append(missing, build.secretEnv)
+	}
+	switch len(missing) {
+	case 1:
+		return nil, fmt.Errorf("environment variable %s must be set", missing[0])
+	case 2:
+		return nil, fmt.Errorf("environment variables %s must be set", strings.Join(missing, " and "))
+	}
+
+	return client, nil
+}
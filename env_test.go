@@ -0,0 +1,70 @@
+package switchbot
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		t.Setenv(DefaultTokenEnv, "env-token")
+		t.Setenv(DefaultSecretEnv, "env-secret")
+
+		client, err := NewClientFromEnv(WithAPIVersion("v1.0"))
+		if err != nil {
+			t.Fatalf("NewClientFromEnv() returned error: %v", err)
+		}
+		if client.token != "env-token" || client.secret != "env-secret" {
+			t.Errorf("credentials = %q, %q; want env-token, env-secret", client.token, client.secret)
+		}
+		if client.apiVersion != "v1.0" {
+			t.Errorf("apiVersion = %q; want options applied", client.apiVersion)
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		t.Setenv(DefaultTokenEnv, "env-token")
+		t.Setenv(DefaultSecretEnv, "")
+
+		_, err := NewClientFromEnv()
+		if err == nil || !strings.Contains(err.Error(), DefaultSecretEnv) || strings.Contains(err.Error(), DefaultTokenEnv) {
+			t.Errorf("NewClientFromEnv() error = %v; want one naming only %s", err, DefaultSecretEnv)
+		}
+	})
+
+	t.Run("CustomNames", func(t *testing.T) {
+		t.Setenv("HOME_SWITCHBOT_TOKEN", "home-token")
+		t.Setenv("HOME_SWITCHBOT_SECRET", "home-secret")
+
+		client, err := NewClientFromEnv(WithEnvNames("HOME_SWITCHBOT_TOKEN", "HOME_SWITCHBOT_SECRET"))
+		if err != nil {
+			t.Fatalf("NewClientFromEnv() returned error: %v", err)
+		}
+		if client.token != "home-token" || client.secret != "home-secret" {
+			t.Errorf("credentials = %q, %q; want home-token, home-secret", client.token, client.secret)
+		}
+
+		_, err = NewClientFromEnv(WithEnvNames("UNSET_TOKEN_ENV", "UNSET_SECRET_ENV"))
+		if err == nil || !strings.Contains(err.Error(), "UNSET_TOKEN_ENV and UNSET_SECRET_ENV") {
+			t.Errorf("NewClientFromEnv() error = %v; want both variables named", err)
+		}
+		if _, err := NewClientFromEnv(WithEnvNames("", "X")); err == nil {
+			t.Error("WithEnvNames with an empty name did not return an error")
+		}
+	})
+
+	t.Run("CredentialsProvider", func(t *testing.T) {
+		t.Setenv(DefaultTokenEnv, "")
+		t.Setenv(DefaultSecretEnv, "")
+
+		provider := func(ctx context.Context) (string, string, error) { return "token", "secret", nil }
+		client, err := NewClientFromEnv(WithCredentialsProvider(provider))
+		if err != nil {
+			t.Fatalf("NewClientFromEnv() with a provider returned error: %v", err)
+		}
+		if client.credentialsProvider == nil {
+			t.Error("credentials provider not set")
+		}
+	})
+}
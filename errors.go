@@ -2,10 +2,33 @@ package switchbot
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 )
 
+// Sentinel errors for well-known SwitchBot status codes.
+// Use errors.Is to match them against errors returned by the client:
+//
+//	if errors.Is(err, switchbot.ErrDeviceOffline) { ... }
+var (
+	ErrUnsupportedDeviceType = &APIError{StatusCode: 151, Message: "device type error"}
+	ErrDeviceNotFound        = &APIError{StatusCode: 152, Message: "device not found"}
+	ErrCommandNotSupported   = &APIError{StatusCode: 160, Message: "command is not supported"}
+	ErrDeviceOffline         = &APIError{StatusCode: 161, Message: "device offline"}
+	ErrHubOffline            = &APIError{StatusCode: 171, Message: "hub device is offline"}
+	ErrDeviceInternal        = &APIError{StatusCode: 190, Message: "device internal error or invalid command format"}
+	ErrUnauthorized          = &APIError{StatusCode: 401, HTTPStatus: 401, Message: "unauthorized"}
+	ErrTooManyRequests       = &APIError{StatusCode: 429, HTTPStatus: 429, Message: "too many requests"}
+)
+
+// ErrDeviceNotInList is returned by helpers that look a device up in the GetDevices list before
+// sending a command when no device has the given ID. Unlike ErrDeviceNotFound, it is not an
+// APIError: the API was never asked about the device.
+var ErrDeviceNotInList = errors.New("device not in device list")
+
 // APIError represents an error response from the SwitchBot API.
 type APIError struct {
 	Body    json.RawMessage `json:"body"`
@@ -14,12 +37,23 @@ type APIError struct {
 	// Underlying HTTP error or context, if any
 	Err error
 
+	// StatusCode is the SwitchBot application status code (e.g. 161), or 0 if the response body did not
+	// carry one or the HTTP status reported a failure (see HTTPStatus).
 	StatusCode int `json:"statusCode"`
+	// HTTPStatus is the HTTP status code of the response (e.g. 200 or 401).
+	HTTPStatus int `json:"-"`
+	// RetryAfter is the delay requested by the response's Retry-After header (e.g. on HTTP 429),
+	// or 0 if there was none.
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (e *APIError) Error() string {
 	var sb strings.Builder // Use strings.Builder for efficient string concatenation
 	sb.WriteString(fmt.Sprintf("SwitchBot API error: statusCode=%d, message='%s'", e.StatusCode, e.Message))
+	// Only a failed HTTP status is worth mentioning; the common HTTP 200 case keeps the original text.
+	if e.HTTPStatus != 0 && e.HTTPStatus != http.StatusOK {
+		sb.WriteString(fmt.Sprintf(", httpStatus=%d", e.HTTPStatus))
+	}
 
 	// Check if the body is non-empty AND not just "{}", "null" etc. before adding it
 	// (Re-using the logic from utils.go/isEmptyJSONBody conceptually)
@@ -37,3 +71,43 @@ func (e *APIError) Error() string {
 
 	return sb.String()
 }
+
+// Is reports whether target is an *APIError with the same StatusCode, or, for targets that
+// set HTTPStatus (ErrUnauthorized, ErrTooManyRequests), the same HTTPStatus.
+// This allows matching against the sentinel errors with errors.Is.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok || e == nil || t == nil {
+		return false
+	}
+	if t.HTTPStatus != 0 && e.HTTPStatus == t.HTTPStatus {
+		return true
+	}
+	return e.StatusCode == t.StatusCode
+}
+
+// Unwrap returns the underlying cause, such as a JSON parse error, so errors.Is and errors.As
+// can match it through the APIError.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatusForAPICode maps a SwitchBot status code to the HTTP status a gateway exposing the API
+// should respond with. Unknown codes map to 502 Bad Gateway.
+func HTTPStatusForAPICode(code int) int {
+	switch code {
+	case 100:
+		return http.StatusOK
+	case 151, 160, 190: // device type error, command not supported, invalid command format
+		return http.StatusBadRequest
+	case 152:
+		return http.StatusNotFound
+	case 161, 171: // device or hub offline
+		return http.StatusServiceUnavailable
+	case 401:
+		return http.StatusUnauthorized
+	case 429:
+		return http.StatusTooManyRequests
+	}
+	return http.StatusBadGateway
+}
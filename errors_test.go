@@ -1,8 +1,11 @@
 package switchbot
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 )
@@ -21,6 +24,15 @@ func TestAPIError_Error(t *testing.T) {
 			},
 			expectedError: "SwitchBot API error: statusCode=161, message='device offline'",
 		},
+		{
+			name: "Error with API status and HTTP status",
+			apiError: APIError{
+				StatusCode: 190,
+				HTTPStatus: 401,
+				Message:    "Unauthorized",
+			},
+			expectedError: "SwitchBot API error: statusCode=190, message='Unauthorized', httpStatus=401",
+		},
 		{
 			name: "Error with status, message, and simple body",
 			apiError: APIError{
@@ -148,3 +160,114 @@ func TestAPIError_Error(t *testing.T) {
 		})
 	}
 }
+
+// TestAPIError_ErrorHTTP200 pins the exact text of the common case, an API error reported in an
+// HTTP 200 response, which must not mention the HTTP status.
+func TestAPIError_ErrorHTTP200(t *testing.T) {
+	client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 161, "message": "device offline", "body": {}}`)
+	})
+
+	_, err := client.GetDeviceStatus(context.Background(), "D1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetDeviceStatus() error = %v; want an APIError", err)
+	}
+	if apiErr.HTTPStatus != http.StatusOK {
+		t.Errorf("HTTPStatus = %d; want 200", apiErr.HTTPStatus)
+	}
+	want := "SwitchBot API error: statusCode=161, message='device offline' (caused by: received API status code 161)"
+	if got := apiErr.Error(); got != want {
+		t.Errorf("Error() = %q; want %q", got, want)
+	}
+}
+
+func TestAPIError_Is(t *testing.T) {
+	offline := &APIError{StatusCode: 161, Message: "device offline", Body: json.RawMessage(`{}`)}
+
+	if !errors.Is(offline, ErrDeviceOffline) {
+		t.Error("errors.Is(offline, ErrDeviceOffline) = false; want true")
+	}
+	if errors.Is(offline, ErrHubOffline) {
+		t.Error("errors.Is(offline, ErrHubOffline) = true; want false")
+	}
+
+	// Wrapped errors should still match
+	wrapped := fmt.Errorf("while turning on lamp: %w", offline)
+	if !errors.Is(wrapped, ErrDeviceOffline) {
+		t.Error("errors.Is(wrapped, ErrDeviceOffline) = false; want true")
+	}
+
+	// Non-APIError targets never match
+	if errors.Is(offline, errors.New("device offline")) {
+		t.Error("errors.Is matched a non-APIError target")
+	}
+
+	// HTTP-level sentinels match on HTTPStatus even when the API code differs
+	httpUnauthorized := &APIError{StatusCode: 190, HTTPStatus: 401}
+	if !errors.Is(httpUnauthorized, ErrUnauthorized) {
+		t.Error("errors.Is(httpUnauthorized, ErrUnauthorized) = false; want true")
+	}
+	if !errors.Is(httpUnauthorized, ErrDeviceInternal) {
+		t.Error("errors.Is(httpUnauthorized, ErrDeviceInternal) = false; want true")
+	}
+	if errors.Is(&APIError{StatusCode: 190, HTTPStatus: 200}, ErrUnauthorized) {
+		t.Error("errors.Is matched ErrUnauthorized for an HTTP 200 response")
+	}
+
+	// Error() output is unaffected
+	if want := "SwitchBot API error: statusCode=161, message='device offline'"; offline.Error() != want {
+		t.Errorf("Error() = %q; want %q", offline.Error(), want)
+	}
+}
+
+func TestHTTPStatusForAPICode(t *testing.T) {
+	testCases := []struct {
+		code int
+		want int
+	}{
+		{100, http.StatusOK},
+		{151, http.StatusBadRequest},
+		{152, http.StatusNotFound},
+		{160, http.StatusBadRequest},
+		{161, http.StatusServiceUnavailable},
+		{171, http.StatusServiceUnavailable},
+		{190, http.StatusBadRequest},
+		{401, http.StatusUnauthorized},
+		{429, http.StatusTooManyRequests},
+		{181, http.StatusBadGateway},
+	}
+	for _, tc := range testCases {
+		if got := HTTPStatusForAPICode(tc.code); got != tc.want {
+			t.Errorf("HTTPStatusForAPICode(%d) = %d; want %d", tc.code, got, tc.want)
+		}
+	}
+}
+
+func TestAPIError_Unwrap(t *testing.T) {
+	sentinel := errors.New("sentinel cause")
+	err := fmt.Errorf("request failed: %w", &APIError{StatusCode: 190, Err: sentinel})
+	if !errors.Is(err, sentinel) {
+		t.Errorf("errors.Is(%v, sentinel) = false; want true", err)
+	}
+	if !errors.Is(err, ErrDeviceInternal) {
+		t.Errorf("errors.Is(%v, ErrDeviceInternal) = false; want true", err)
+	}
+	if errors.Is(&APIError{StatusCode: 190}, sentinel) {
+		t.Error("errors.Is matched a sentinel on an APIError without a cause")
+	}
+
+	t.Run("ParseErrorReachable", func(t *testing.T) {
+		client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "not json")
+		})
+
+		_, err := client.GetDevices(context.Background())
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("errors.As(%v, *json.SyntaxError) = false; want the parse error", err)
+		}
+	})
+}
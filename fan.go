@@ -0,0 +1,140 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// fanDeviceTypes are the fans controlled by the fan helpers. Air purifiers use a different
+// command set (setMode with a fan gear) and are not supported.
+var fanDeviceTypes = []string{DeviceTypeBatteryFan, DeviceTypeCirculatorFan}
+
+// FanMode is the wind mode of a Circulator Fan.
+type FanMode string
+
+const (
+	FanModeDirect  FanMode = "direct"
+	FanModeNatural FanMode = "natural"
+	FanModeSleep   FanMode = "sleep"
+	FanModeBaby    FanMode = "baby" // Ultra-quiet mode
+)
+
+// FanStatus represents the status of a Battery Circulator Fan or Circulator Fan.
+type FanStatus struct {
+	reportedStatus // Provides ReportedAt
+
+	DeviceID    string     `json:"deviceId"`
+	DeviceType  string     `json:"deviceType"`
+	HubDeviceID string     `json:"hubDeviceId"`
+	Version     string     `json:"version"`
+	Power       PowerState `json:"power"`
+	Mode        FanMode    `json:"mode"`
+	Speed       FlexInt    `json:"fanSpeed"` // Fan speed (1-100)
+	Battery     FlexInt    `json:"battery"`  // Percentage (0-100); 0 for the mains-powered Circulator Fan
+	Oscillating bool       `json:"-"`        // Decoded from "oscillation": "on"
+	_           struct{}
+}
+
+// UnmarshalJSON decodes the status and maps the "oscillation" on/off string to Oscillating.
+func (s *FanStatus) UnmarshalJSON(data []byte) error {
+	type plain FanStatus
+	aux := struct {
+		*plain
+		Oscillation PowerState `json:"oscillation"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	s.Oscillating = aux.Oscillation == PowerStateOn
+	return nil
+}
+
+// GetFanStatus retrieves the typed status of a Battery Circulator Fan or Circulator Fan.
+// Returns ErrDeviceTypeMismatch for any other device type.
+func (c *Client) GetFanStatus(ctx context.Context, deviceID string) (*FanStatus, error) {
+	var status FanStatus
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, fanDeviceTypes...); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SetFanMode sets the wind mode of a Battery Circulator Fan or Circulator Fan.
+// The device type is read first (one extra API call) and ErrDeviceTypeMismatch is returned
+// for any other device; an unknown mode returns ErrInvalidParameter without any API call.
+func (c *Client) SetFanMode(ctx context.Context, deviceID string, mode FanMode) error {
+	if err := checkFanMode(mode); err != nil {
+		return err
+	}
+	return c.sendFanCommand(ctx, deviceID, "setWindMode", string(mode))
+}
+
+// SetFanSpeed sets the speed (1-100) of a Battery Circulator Fan or Circulator Fan.
+// The device type is read first (one extra API call) and ErrDeviceTypeMismatch is returned
+// for any other device; an out-of-range speed returns ErrInvalidParameter without any API call.
+func (c *Client) SetFanSpeed(ctx context.Context, deviceID string, speed int) error {
+	if err := checkRange("fan speed", speed, 1, 100); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	return c.sendFanCommand(ctx, deviceID, "setWindSpeed", speed)
+}
+
+// SetCirculatorFanAll sets the wind mode and speed (1-100) of a Battery Circulator Fan or
+// Circulator Fan, then turns it on or off. The API has no combined fan command, so this sends
+// setWindMode, setWindSpeed and turnOn or turnOff in turn and stops at the first failure, which
+// may leave the earlier settings applied.
+//
+// Invalid values return ErrInvalidParameter without any API call. The device type is read once
+// first (one extra API call) and ErrDeviceTypeMismatch is returned for any other device.
+func (c *Client) SetCirculatorFanAll(ctx context.Context, deviceID string, power PowerState, mode FanMode, speed int) error {
+	if power != PowerStateOn && power != PowerStateOff {
+		return fmt.Errorf("%w: power state %q must be on or off", ErrInvalidParameter, string(power))
+	}
+	if err := checkFanMode(mode); err != nil {
+		return err
+	}
+	if err := checkRange("fan speed", speed, 1, 100); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	if _, err := c.GetFanStatus(ctx, deviceID); err != nil {
+		return err
+	}
+
+	powerCommand := "turnOff"
+	if power == PowerStateOn {
+		powerCommand = "turnOn"
+	}
+	commands := []struct {
+		command   string
+		parameter interface{}
+	}{
+		{"setWindMode", string(mode)},
+		{"setWindSpeed", speed},
+		{powerCommand, nil},
+	}
+	for _, cmd := range commands {
+		if _, err := c.SendDeviceCommand(ctx, deviceID, cmd.command, cmd.parameter, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFanMode returns ErrInvalidParameter for an unknown fan mode.
+func checkFanMode(mode FanMode) error {
+	switch mode {
+	case FanModeDirect, FanModeNatural, FanModeSleep, FanModeBaby:
+		return nil
+	}
+	return fmt.Errorf("%w: fan mode %q must be direct, natural, sleep or baby", ErrInvalidParameter, string(mode))
+}
+
+// sendFanCommand verifies that deviceID is a supported fan and sends the command.
+func (c *Client) sendFanCommand(ctx context.Context, deviceID, command string, parameter interface{}) error {
+	if _, err := c.GetFanStatus(ctx, deviceID); err != nil {
+		return err
+	}
+	_, err := c.SendDeviceCommand(ctx, deviceID, command, parameter, "")
+	return err
+}
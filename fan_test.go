@@ -0,0 +1,132 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// fanHandler serves a status of the given deviceType for GET requests and captures commands.
+func fanHandler(t *testing.T, deviceType string, got *[]capturedCommand) http.HandlerFunc {
+	capture := commandCaptureHandler(t, got)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			statusHandler(fmt.Sprintf(`{"deviceId": "F1", "deviceType": %q, "power": "on", "mode": "natural", "fanSpeed": "40", "battery": "75", "oscillation": "on", "nightStatus": 0}`, deviceType))(w, r)
+			return
+		}
+		capture(w, r)
+	}
+}
+
+func TestGetFanStatus(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, fanHandler(t, DeviceTypeBatteryFan, &got))
+
+	status, err := client.GetFanStatus(context.Background(), "F1")
+	if err != nil {
+		t.Fatalf("GetFanStatus() returned error: %v", err)
+	}
+	if status.Power != PowerStateOn || status.Mode != FanModeNatural || status.Speed != 40 || status.Battery != 75 || !status.Oscillating {
+		t.Errorf("GetFanStatus() = %+v", *status)
+	}
+
+	client, _ = setupMockServer(t, fanHandler(t, DeviceTypeBot, &got))
+	if _, err := client.GetFanStatus(context.Background(), "F1"); !errors.Is(err, ErrDeviceTypeMismatch) {
+		t.Errorf("GetFanStatus() error = %v; want ErrDeviceTypeMismatch", err)
+	}
+}
+
+func TestFanCommands(t *testing.T) {
+	t.Run("SetFanMode", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, fanHandler(t, DeviceTypeCirculatorFan, &got))
+
+		if err := client.SetFanMode(context.Background(), "F1", FanModeSleep); err != nil {
+			t.Fatalf("SetFanMode() returned error: %v", err)
+		}
+		if len(got) != 1 || got[0].Command != "setWindMode" || got[0].Parameter != "sleep" {
+			t.Errorf("commands = %+v; want setWindMode(sleep)", got)
+		}
+	})
+
+	t.Run("SetFanSpeed", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, fanHandler(t, DeviceTypeBatteryFan, &got))
+
+		if err := client.SetFanSpeed(context.Background(), "F1", 65); err != nil {
+			t.Fatalf("SetFanSpeed() returned error: %v", err)
+		}
+		if len(got) != 1 || got[0].Command != "setWindSpeed" || got[0].Parameter != float64(65) {
+			t.Errorf("commands = %+v; want setWindSpeed(65)", got)
+		}
+	})
+
+	t.Run("SetCirculatorFanAll", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, fanHandler(t, DeviceTypeBatteryFan, &got))
+
+		if err := client.SetCirculatorFanAll(context.Background(), "F1", PowerStateOn, FanModeDirect, 60); err != nil {
+			t.Fatalf("SetCirculatorFanAll() returned error: %v", err)
+		}
+		if len(got) != 3 ||
+			got[0].Command != "setWindMode" || got[0].Parameter != "direct" ||
+			got[1].Command != "setWindSpeed" || got[1].Parameter != float64(60) ||
+			got[2].Command != "turnOn" {
+			t.Errorf("commands = %+v; want setWindMode(direct), setWindSpeed(60), turnOn", got)
+		}
+
+		got = nil
+		if err := client.SetCirculatorFanAll(context.Background(), "F1", PowerStateOff, FanModeBaby, 20); err != nil {
+			t.Fatalf("SetCirculatorFanAll() returned error: %v", err)
+		}
+		if len(got) != 3 || got[0].Parameter != "baby" || got[2].Command != "turnOff" {
+			t.Errorf("commands = %+v; want setWindMode(baby), setWindSpeed(20), turnOff", got)
+		}
+	})
+
+	t.Run("InvalidValues", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, fanHandler(t, DeviceTypeBatteryFan, &got))
+
+		if err := client.SetFanMode(context.Background(), "F1", "turbo"); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("SetFanMode(turbo) error = %v; want ErrInvalidParameter", err)
+		}
+		for _, speed := range []int{0, 101} {
+			if err := client.SetFanSpeed(context.Background(), "F1", speed); !errors.Is(err, ErrInvalidParameter) {
+				t.Errorf("SetFanSpeed(%d) error = %v; want ErrInvalidParameter", speed, err)
+			}
+		}
+		invalidAll := []struct {
+			power PowerState
+			mode  FanMode
+			speed int
+		}{
+			{"", FanModeDirect, 50},
+			{PowerStateOn, "turbo", 50},
+			{PowerStateOn, FanModeNatural, 0},
+			{PowerStateOn, FanModeSleep, 101},
+		}
+		for _, tc := range invalidAll {
+			if err := client.SetCirculatorFanAll(context.Background(), "F1", tc.power, tc.mode, tc.speed); !errors.Is(err, ErrInvalidParameter) {
+				t.Errorf("SetCirculatorFanAll(%q, %q, %d) error = %v; want ErrInvalidParameter", tc.power, tc.mode, tc.speed, err)
+			}
+		}
+		if len(got) != 0 {
+			t.Errorf("sent %d commands; invalid values should not be sent", len(got))
+		}
+	})
+
+	t.Run("DeviceTypeMismatch", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, fanHandler(t, DeviceTypeHumidifier, &got))
+
+		if err := client.SetFanSpeed(context.Background(), "F1", 50); !errors.Is(err, ErrDeviceTypeMismatch) {
+			t.Errorf("SetFanSpeed() error = %v; want ErrDeviceTypeMismatch", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("sent %d commands to a non-fan device", len(got))
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package switchbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FlexInt is an integer that decodes from either a JSON number or a numeric JSON string.
+// Depending on firmware version, SwitchBot reports some status fields as "battery": 85
+// or as "battery": "85". The typed status structs use FlexInt for battery, humidity,
+// lightLevel and CO2. null and "" decode to 0.
+type FlexInt int
+
+// Int returns the value as an int.
+func (n FlexInt) Int() int {
+	return int(n)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *FlexInt) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	raw := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		raw = strings.TrimSpace(s)
+		if raw == "" {
+			*n = 0
+			return nil
+		}
+	}
+
+	if v, err := strconv.Atoi(raw); err == nil {
+		*n = FlexInt(v)
+		return nil
+	}
+	// Some firmware reports whole numbers with a fractional part, e.g. 85.0.
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || f != float64(int(f)) {
+		return fmt.Errorf("invalid integer value %s", string(data))
+	}
+	*n = FlexInt(f)
+	return nil
+}
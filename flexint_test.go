@@ -0,0 +1,57 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestFlexIntUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    FlexInt
+		wantErr bool
+	}{
+		{`85`, 85, false},
+		{`"85"`, 85, false},
+		{`" 85 "`, 85, false},
+		{`85.0`, 85, false},
+		{`""`, 0, false},
+		{`null`, 0, false},
+		{`85.5`, 0, true},
+		{`"high"`, 0, true},
+		{`true`, 0, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			var n FlexInt
+			err := json.Unmarshal([]byte(tc.input), &n)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v; wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if n != tc.want {
+				t.Errorf("Unmarshal(%s) = %d; want %d", tc.input, n, tc.want)
+			}
+		})
+	}
+}
+
+func TestTypedStatusFlexibleBattery(t *testing.T) {
+	for _, battery := range []string{`85`, `"85"`} {
+		t.Run(battery, func(t *testing.T) {
+			body := `{"deviceId": "M1", "deviceType": "Meter", "hubDeviceId": "H1", "temperature": 21.5, "humidity": "45", "battery": ` + battery + `, "version": "V1"}`
+			client, _ := setupMockServer(t, statusHandler(body))
+
+			status, err := client.GetMeterStatus(context.Background(), "M1")
+			if err != nil {
+				t.Fatalf("GetMeterStatus() returned error: %v", err)
+			}
+			if status.Battery != 85 {
+				t.Errorf("Battery = %d; want 85", status.Battery)
+			}
+			if status.Humidity != 45 {
+				t.Errorf("Humidity = %d; want 45", status.Humidity)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetHumidifierStatus(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(`{"deviceId": "H1", "deviceType": "Humidifier", "hubDeviceId": "000000000000", "power": "on", "humidity": 45, "temperature": 22.5, "nebulizationEfficiency": 30, "auto": true, "childLock": false, "sound": true, "lackWater": true}`))
+
+	status, err := client.GetHumidifierStatus(context.Background(), "H1")
+	if err != nil {
+		t.Fatalf("GetHumidifierStatus() returned error: %v", err)
+	}
+	if status.Power != PowerStateOn || status.Humidity != 45 || status.Temperature != 22.5 || status.NebulizationEfficiency != 30 {
+		t.Errorf("numeric/power fields not decoded: %+v", *status)
+	}
+	if !status.Auto || status.ChildLock || !status.Sound || !status.LackWater {
+		t.Errorf("boolean fields not decoded: %+v", *status)
+	}
+}
+
+func TestSetHumidifierMode(t *testing.T) {
+	testCases := []struct {
+		mode          HumidifierMode
+		wantParameter interface{}
+	}{
+		{HumidifierModeAuto, "auto"},
+		{HumidifierModeLow, float64(101)},
+		{HumidifierModeHigh, float64(103)},
+		{HumidifierPercent(0), float64(0)},
+		{HumidifierPercent(65), float64(65)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.mode.String(), func(t *testing.T) {
+			var got []capturedCommand
+			client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+			if err := client.SetHumidifierMode(context.Background(), "H1", tc.mode); err != nil {
+				t.Fatalf("SetHumidifierMode() returned error: %v", err)
+			}
+			if len(got) != 1 || got[0].Command != "setMode" || got[0].Parameter != tc.wantParameter {
+				t.Errorf("received %+v; want setMode(%v)", got, tc.wantParameter)
+			}
+		})
+	}
+
+	t.Run("OutOfRange", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+		for _, mode := range []HumidifierMode{HumidifierPercent(-5), HumidifierPercent(150)} {
+			if err := client.SetHumidifierMode(context.Background(), "H1", mode); !errors.Is(err, ErrInvalidParameter) {
+				t.Errorf("SetHumidifierMode(%d) error = %v; want ErrInvalidParameter", int(mode), err)
+			}
+		}
+		if len(got) != 0 {
+			t.Errorf("sent %d commands for invalid modes; want 0", len(got))
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+)
+
+// defaultIdempotencyWindow is how long SendDeviceCommandIdempotent remembers a key.
+const defaultIdempotencyWindow = time.Minute
+
+// idempotentCommand is the outcome of a command sent by SendDeviceCommandIdempotent.
+// done is closed once resp and err are set.
+type idempotentCommand struct {
+	done    chan struct{}
+	resp    CommandResponse
+	err     error
+	expires time.Time
+}
+
+// SendDeviceCommandIdempotent is SendDeviceCommand deduplicated by key: within one minute of a
+// successful send, calls with the same key return the first response without sending the command
+// again, so retrying a press or toggle cannot fire it twice. A call made while the first is still
+// in flight waits for its result. Keys are scoped to the Client and are not tied to deviceID or
+// command, so use a fresh key (e.g. a UUID) for each logical action.
+//
+// Trade-offs:
+//   - A failed send releases its key so the caller can retry. When the outcome is unknown
+//     (e.g. a timeout after the API received the request), that retry may still execute the
+//     command twice; the guard only suppresses duplicates of sends known to have succeeded.
+//   - Keys live in memory only and are not shared between Client instances or processes.
+//   - Rate-limited (HTTP 429) requests are never processed by the API, so WithRateLimitRetry
+//     is safe without this guard; it protects against retries made by the caller.
+func (c *Client) SendDeviceCommandIdempotent(ctx context.Context, key, deviceID, command string, parameter interface{}, commandType string) (CommandResponse, error) {
+	if key == "" {
+		return nil, fmt.Errorf("idempotency key cannot be empty")
+	}
+
+	c.mu.Lock()
+	now := time.Now()
+	for k, entry := range c.idempotentCommands {
+		if entry.isDone() && now.After(entry.expires) {
+			delete(c.idempotentCommands, k)
+		}
+	}
+	if entry, ok := c.idempotentCommands[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if entry.err != nil {
+			// The earlier send failed and released the key; try again.
+			return c.SendDeviceCommandIdempotent(ctx, key, deviceID, command, parameter, commandType)
+		}
+		return maps.Clone(entry.resp), nil
+	}
+	entry := &idempotentCommand{done: make(chan struct{})}
+	if c.idempotentCommands == nil {
+		c.idempotentCommands = make(map[string]*idempotentCommand)
+	}
+	c.idempotentCommands[key] = entry
+	c.mu.Unlock()
+
+	resp, err := c.SendDeviceCommand(ctx, deviceID, command, parameter, commandType)
+
+	c.mu.Lock()
+	entry.resp, entry.err = resp, err
+	entry.expires = time.Now().Add(c.idempotencyWindow)
+	if err != nil {
+		delete(c.idempotentCommands, key)
+	}
+	close(entry.done)
+	c.mu.Unlock()
+
+	return maps.Clone(resp), err
+}
+
+// isDone reports whether the command has completed.
+func (e *idempotentCommand) isDone() bool {
+	select {
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
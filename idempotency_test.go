@@ -0,0 +1,93 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendDeviceCommandIdempotent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Deduplicates", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.SendDeviceCommandIdempotent(ctx, "press-1", "B1", "press", nil, ""); err != nil {
+					t.Errorf("SendDeviceCommandIdempotent() returned error: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+		if len(got) != 1 {
+			t.Errorf("sent %d commands for one key; want 1", len(got))
+		}
+
+		if _, err := client.SendDeviceCommandIdempotent(ctx, "press-2", "B1", "press", nil, ""); err != nil {
+			t.Fatalf("SendDeviceCommandIdempotent() returned error: %v", err)
+		}
+		if len(got) != 2 {
+			t.Errorf("sent %d commands for two keys; want 2", len(got))
+		}
+	})
+
+	t.Run("WindowExpired", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, commandCaptureHandler(t, &got), withIdempotencyWindow(0))
+
+		for range 2 {
+			if _, err := client.SendDeviceCommandIdempotent(ctx, "press", "B1", "press", nil, ""); err != nil {
+				t.Fatalf("SendDeviceCommandIdempotent() returned error: %v", err)
+			}
+		}
+		if len(got) != 2 {
+			t.Errorf("sent %d commands after the window expired; want 2", len(got))
+		}
+	})
+
+	t.Run("FailureReleasesKey", func(t *testing.T) {
+		var calls atomic.Int32
+		var got []capturedCommand
+		capture := commandCaptureHandler(t, &got)
+		client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			capture(w, r)
+		})
+
+		if _, err := client.SendDeviceCommandIdempotent(ctx, "press", "B1", "press", nil, ""); err == nil {
+			t.Fatal("SendDeviceCommandIdempotent() did not return the server error")
+		}
+		if _, err := client.SendDeviceCommandIdempotent(ctx, "press", "B1", "press", nil, ""); err != nil {
+			t.Fatalf("retried SendDeviceCommandIdempotent() returned error: %v", err)
+		}
+		if len(got) != 1 {
+			t.Errorf("retry sent %d commands; want 1", len(got))
+		}
+	})
+
+	t.Run("EmptyKey", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(`{}`))
+		if _, err := client.SendDeviceCommandIdempotent(ctx, "", "B1", "press", nil, ""); err == nil {
+			t.Error("SendDeviceCommandIdempotent() with an empty key did not return an error")
+		}
+	})
+}
+
+// withIdempotencyWindow overrides how long idempotency keys are remembered.
+func withIdempotencyWindow(window time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.idempotencyWindow = window
+		return nil
+	}
+}
@@ -0,0 +1,119 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetBrightness sets the brightness of a light in percent (1-100).
+// Supported by Color Bulb, Strip Light, Ceiling Light and Ceiling Light Pro; the device type is not checked.
+func (c *Client) SetBrightness(ctx context.Context, deviceID string, pct int) error {
+	if err := checkRange("brightness", pct, 1, 100); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	_, err := c.SendDeviceCommand(ctx, deviceID, "setBrightness", pct, "")
+	return err
+}
+
+// SetColorTemperature sets the color temperature of a light in Kelvin (2700-6500).
+// Supported by Color Bulb, Ceiling Light and Ceiling Light Pro; the device type is not checked.
+func (c *Client) SetColorTemperature(ctx context.Context, deviceID string, kelvin int) error {
+	if err := checkRange("color temperature", kelvin, 2700, 6500); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	_, err := c.SendDeviceCommand(ctx, deviceID, "setColorTemperature", kelvin, "")
+	return err
+}
+
+// LightColor is an RGB color for the setColor command. Each component is 0-255.
+type LightColor struct {
+	R int // Red component
+	G int // Green component
+	B int // Blue component
+	_ struct{}
+}
+
+// String returns the color in the "R:G:B" form expected by setColor.
+func (c LightColor) String() string {
+	return fmt.Sprintf("%d:%d:%d", c.R, c.G, c.B)
+}
+
+// LightState is the desired state of a Color Bulb or Strip Light.
+// Zero-valued fields are left unchanged.
+type LightState struct {
+	Power            PowerState  // PowerStateOn or PowerStateOff; "" leaves power unchanged
+	Brightness       int         // 1-100; 0 leaves brightness unchanged
+	Color            *LightColor // nil leaves the color unchanged
+	ColorTemperature int         // 2700-6500 (Color Bulb only); 0 leaves it unchanged
+	_                struct{}
+}
+
+// validate checks every field of the state before any command is sent.
+func (s LightState) validate() error {
+	switch s.Power {
+	case "", PowerStateOn, PowerStateOff:
+	default:
+		return fmt.Errorf("%w: power %q must be on or off", ErrInvalidParameter, string(s.Power))
+	}
+	if s.Brightness != 0 {
+		if err := checkRange("brightness", s.Brightness, 1, 100); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+	}
+	if s.Color != nil {
+		for _, component := range []int{s.Color.R, s.Color.G, s.Color.B} {
+			if err := checkRange("color component", component, 0, 255); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+			}
+		}
+	}
+	if s.ColorTemperature != 0 {
+		if s.Color != nil {
+			return fmt.Errorf("%w: color and color temperature cannot both be set", ErrInvalidParameter)
+		}
+		if err := checkRange("color temperature", s.ColorTemperature, 2700, 6500); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+	}
+	return nil
+}
+
+// commands returns the commands needed to reach the state, in the order they must be sent.
+// Power-on goes first so the remaining settings apply to a lit device; power-off goes last
+// so that setting brightness or color does not turn the light back on.
+func (s LightState) commands() []CommandRequest {
+	var cmds []CommandRequest
+	if s.Power == PowerStateOn {
+		cmds = append(cmds, CommandRequest{Command: "turnOn", CommandType: "command"})
+	}
+	if s.Brightness != 0 {
+		cmds = append(cmds, CommandRequest{Command: "setBrightness", Parameter: s.Brightness, CommandType: "command"})
+	}
+	if s.Color != nil {
+		cmds = append(cmds, CommandRequest{Command: "setColor", Parameter: s.Color.String(), CommandType: "command"})
+	}
+	if s.ColorTemperature != 0 {
+		cmds = append(cmds, CommandRequest{Command: "setColorTemperature", Parameter: s.ColorTemperature, CommandType: "command"})
+	}
+	if s.Power == PowerStateOff {
+		cmds = append(cmds, CommandRequest{Command: "turnOff", CommandType: "command"})
+	}
+	return cmds
+}
+
+// SetLightState applies power, brightness and color to a Color Bulb or Strip Light.
+// The API has no combined command, so SetLightState sends one command per set field in order
+// (turnOn, setBrightness, setColor, setColorTemperature, turnOff) and stops on the first error;
+// commands sent before the failure are not rolled back. All fields are validated before anything
+// is sent, and invalid values return ErrInvalidParameter. The device type is not checked.
+func (c *Client) SetLightState(ctx context.Context, deviceID string, state LightState) error {
+	if err := state.validate(); err != nil {
+		return err
+	}
+	for _, cmd := range state.commands() {
+		if _, err := c.SendDeviceCommand(ctx, deviceID, cmd.Command, cmd.Parameter, cmd.CommandType); err != nil {
+			return fmt.Errorf("%s: %w", cmd.Command, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSetBrightness(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+	if err := client.SetBrightness(context.Background(), "L1", 75); err != nil {
+		t.Fatalf("SetBrightness() returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].Command != "setBrightness" || got[0].Parameter != float64(75) {
+		t.Errorf("received %+v; want setBrightness(75)", got)
+	}
+
+	for _, pct := range []int{0, 101} {
+		err := client.SetBrightness(context.Background(), "L1", pct)
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("SetBrightness(%d) error = %v; want ErrInvalidParameter", pct, err)
+		} else if !strings.Contains(err.Error(), "brightness") {
+			t.Errorf("SetBrightness(%d) error %q does not describe the field", pct, err)
+		}
+	}
+	if len(got) != 1 {
+		t.Errorf("sent %d commands; invalid values should not be sent", len(got))
+	}
+}
+
+func TestSetColorTemperature(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+	if err := client.SetColorTemperature(context.Background(), "L1", 4000); err != nil {
+		t.Fatalf("SetColorTemperature() returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].Command != "setColorTemperature" || got[0].Parameter != float64(4000) {
+		t.Errorf("received %+v; want setColorTemperature(4000)", got)
+	}
+
+	for _, kelvin := range []int{2699, 6501} {
+		if err := client.SetColorTemperature(context.Background(), "L1", kelvin); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("SetColorTemperature(%d) error = %v; want ErrInvalidParameter", kelvin, err)
+		}
+	}
+	if len(got) != 1 {
+		t.Errorf("sent %d commands; invalid values should not be sent", len(got))
+	}
+}
+
+func TestSetLightState(t *testing.T) {
+	t.Run("TurnOn", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+		state := LightState{Power: PowerStateOn, Brightness: 80, Color: &LightColor{R: 255, G: 128}}
+		if err := client.SetLightState(context.Background(), "L1", state); err != nil {
+			t.Fatalf("SetLightState() returned error: %v", err)
+		}
+		want := []capturedCommand{
+			{Command: "turnOn", Parameter: "default"},
+			{Command: "setBrightness", Parameter: float64(80)},
+			{Command: "setColor", Parameter: "255:128:0"},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("received %d commands; want %d", len(got), len(want))
+		}
+		for i := range want {
+			if got[i].Command != want[i].Command || got[i].Parameter != want[i].Parameter {
+				t.Errorf("command %d = %s(%v); want %s(%v)", i, got[i].Command, got[i].Parameter, want[i].Command, want[i].Parameter)
+			}
+		}
+	})
+
+	t.Run("TurnOffLast", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+		if err := client.SetLightState(context.Background(), "L1", LightState{Power: PowerStateOff, Brightness: 10}); err != nil {
+			t.Fatalf("SetLightState() returned error: %v", err)
+		}
+		if len(got) != 2 || got[0].Command != "setBrightness" || got[1].Command != "turnOff" {
+			t.Errorf("received %+v; want setBrightness then turnOff", got)
+		}
+	})
+
+	t.Run("StopsOnFirstError", func(t *testing.T) {
+		var calls int
+		client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusOK)
+			if calls == 2 {
+				fmt.Fprintln(w, `{"statusCode": 161, "message": "device offline", "body": {}}`)
+				return
+			}
+			fmt.Fprintln(w, `{"statusCode": 100, "message": "success", "body": {}}`)
+		})
+
+		err := client.SetLightState(context.Background(), "L1", LightState{Power: PowerStateOn, Brightness: 50, Color: &LightColor{B: 255}})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !strings.Contains(err.Error(), "setBrightness") {
+			t.Errorf("SetLightState() error = %v; want APIError from setBrightness", err)
+		}
+		if calls != 2 {
+			t.Errorf("sent %d commands; want to stop after the failing one", calls)
+		}
+	})
+
+	t.Run("ValidatesUpFront", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+		invalid := []LightState{
+			{Power: "dim"},
+			{Power: PowerStateOn, Brightness: 101},
+			{Power: PowerStateOn, Color: &LightColor{R: 256}},
+			{Power: PowerStateOn, Color: &LightColor{G: -1}},
+			{ColorTemperature: 2000},
+			{Color: &LightColor{}, ColorTemperature: 4000},
+		}
+		for _, state := range invalid {
+			if err := client.SetLightState(context.Background(), "L1", state); !errors.Is(err, ErrInvalidParameter) {
+				t.Errorf("SetLightState(%+v) error = %v; want ErrInvalidParameter", state, err)
+			}
+		}
+		if len(got) != 0 {
+			t.Errorf("sent %d commands; invalid states should not send anything", len(got))
+		}
+	})
+}
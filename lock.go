@@ -0,0 +1,107 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+)
+
+// lockDeviceTypes are the locks read by GetLockStatus.
+var lockDeviceTypes = []string{DeviceTypeSmartLock, DeviceTypeSmartLockPro}
+
+// DoorState is the door position reported by a Smart Lock's door sensor.
+type DoorState string
+
+const (
+	DoorStateOpened DoorState = "opened"
+	DoorStateClosed DoorState = "closed"
+)
+
+// LockStatus represents the status of a Smart Lock or Smart Lock Pro.
+// LockState is locked, unlocked or jammed; a Smart Lock Pro may also report deadbolt or latch,
+// which GetLockProStatus exposes through LockProStatus.
+type LockStatus struct {
+	reportedStatus // Provides ReportedAt
+
+	DeviceID    string    `json:"deviceId"`
+	DeviceType  string    `json:"deviceType"`
+	HubDeviceID string    `json:"hubDeviceId"`
+	Version     string    `json:"version"`
+	LockState   LockState `json:"lockState"`
+	DoorState   DoorState `json:"doorState"`
+	Calibrate   bool      `json:"calibrate"` // Whether the lock has been calibrated
+	Battery     FlexInt   `json:"battery"`   // Percentage (0-100)
+	_           struct{}
+}
+
+// IsPro reports whether the status was read from a Smart Lock Pro.
+func (s *LockStatus) IsPro() bool {
+	return s.DeviceType == DeviceTypeSmartLockPro
+}
+
+// LockProStatus represents the status of a Smart Lock Pro, whose multi-point lock can also
+// report LockStateDeadbolt and LockStateLatch.
+type LockProStatus struct {
+	LockStatus
+}
+
+// IsSecured reports whether the door is held by the deadbolt, i.e. locked or in deadbolt mode.
+// The latch alone keeps the door shut but does not lock it.
+func (s *LockProStatus) IsSecured() bool {
+	return s.LockState == LockStateLocked || s.LockState == LockStateDeadbolt
+}
+
+// GetLockStatus retrieves the typed status of a Smart Lock or Smart Lock Pro; use IsPro to tell
+// them apart, or GetLockProStatus for the Pro's additional states.
+// Returns ErrDeviceTypeMismatch for any other device type.
+func (c *Client) GetLockStatus(ctx context.Context, deviceID string) (*LockStatus, error) {
+	var status LockStatus
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, lockDeviceTypes...); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetLockProStatus retrieves the typed status of a Smart Lock Pro.
+// Returns ErrDeviceTypeMismatch for a basic Smart Lock or any other device type.
+func (c *Client) GetLockProStatus(ctx context.Context, deviceID string) (*LockProStatus, error) {
+	var status LockProStatus
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, DeviceTypeSmartLockPro); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// LockSmartLock locks a Smart Lock or Smart Lock Pro (the lock command).
+func (c *Client) LockSmartLock(ctx context.Context, deviceID string) error {
+	_, err := c.SendDeviceCommand(ctx, deviceID, "lock", nil, "")
+	return err
+}
+
+// UnlockSmartLock unlocks a Smart Lock or Smart Lock Pro (the unlock command).
+func (c *Client) UnlockSmartLock(ctx context.Context, deviceID string) error {
+	_, err := c.SendDeviceCommand(ctx, deviceID, "unlock", nil, "")
+	return err
+}
+
+// DeadboltSmartLockPro switches a Smart Lock Pro to deadbolt mode (the deadbolt command), which
+// basic Smart Locks do not support. The device type is read from the device list first (one
+// extra API call) and ErrDeviceTypeMismatch is returned for a basic Smart Lock or any other device,
+// or ErrDeviceNotInList if the list has no device with that ID.
+func (c *Client) DeadboltSmartLockPro(ctx context.Context, deviceID string) error {
+	if deviceID == "" {
+		return fmt.Errorf("deviceID cannot be empty")
+	}
+	devices, err := c.GetDevices(ctx)
+	if err != nil {
+		return err
+	}
+	deviceType, ok := devices.deviceType(deviceID)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrDeviceNotInList, deviceID)
+	}
+	if deviceType != DeviceTypeSmartLockPro {
+		return fmt.Errorf("%w: device %s is %q, want %q", ErrDeviceTypeMismatch, deviceID, string(deviceType), DeviceTypeSmartLockPro)
+	}
+	_, err = c.SendDeviceCommand(ctx, deviceID, "deadbolt", nil, "")
+	return err
+}
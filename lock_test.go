@@ -0,0 +1,109 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// lockHandler serves a device list and a lock status of the given deviceType for GET requests and
+// captures commands.
+func lockHandler(t *testing.T, deviceType string, lockState LockState, got *[]capturedCommand) http.HandlerFunc {
+	capture := commandCaptureHandler(t, got)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/devices") {
+			statusHandler(fmt.Sprintf(`{"deviceList": [{"deviceId": "L1", "deviceType": %q}], "infraredRemoteList": []}`, deviceType))(w, r)
+			return
+		}
+		if r.Method == http.MethodGet {
+			statusHandler(fmt.Sprintf(`{"deviceId": "L1", "deviceType": %q, "hubDeviceId": "H1", "lockState": %q, "doorState": "closed", "calibrate": true, "battery": 70, "version": "V2.1"}`, deviceType, lockState))(w, r)
+			return
+		}
+		capture(w, r)
+	}
+}
+
+func TestGetLockStatus(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, lockHandler(t, DeviceTypeSmartLock, LockStateLocked, &got))
+
+	status, err := client.GetLockStatus(context.Background(), "L1")
+	if err != nil {
+		t.Fatalf("GetLockStatus() returned error: %v", err)
+	}
+	if status.IsPro() || status.LockState != LockStateLocked || status.DoorState != DoorStateClosed || !status.Calibrate || status.Battery != 70 {
+		t.Errorf("GetLockStatus() = %+v", *status)
+	}
+	if _, err := client.GetLockProStatus(context.Background(), "L1"); !errors.Is(err, ErrDeviceTypeMismatch) {
+		t.Errorf("GetLockProStatus() on a Smart Lock error = %v; want ErrDeviceTypeMismatch", err)
+	}
+
+	client, _ = setupMockServer(t, lockHandler(t, DeviceTypeSmartLockPro, LockStateDeadbolt, &got))
+	status, err = client.GetLockStatus(context.Background(), "L1")
+	if err != nil {
+		t.Fatalf("GetLockStatus() returned error: %v", err)
+	}
+	if !status.IsPro() {
+		t.Error("IsPro() = false for a Smart Lock Pro")
+	}
+	pro, err := client.GetLockProStatus(context.Background(), "L1")
+	if err != nil {
+		t.Fatalf("GetLockProStatus() returned error: %v", err)
+	}
+	if pro.LockState != LockStateDeadbolt || !pro.IsSecured() {
+		t.Errorf("GetLockProStatus() = %+v; want a secured deadbolt state", *pro)
+	}
+	if pro.DeviceID != "L1" || pro.Battery != 70 {
+		t.Errorf("GetLockProStatus() = %+v; want the embedded LockStatus fields decoded", *pro)
+	}
+	if (&LockProStatus{LockStatus{LockState: LockStateLatch}}).IsSecured() {
+		t.Error("IsSecured() = true for the latch state; want false")
+	}
+}
+
+func TestLockCommands(t *testing.T) {
+	t.Run("LockUnlock", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, lockHandler(t, DeviceTypeSmartLock, LockStateUnlocked, &got))
+
+		if err := client.LockSmartLock(context.Background(), "L1"); err != nil {
+			t.Fatalf("LockSmartLock() returned error: %v", err)
+		}
+		if err := client.UnlockSmartLock(context.Background(), "L1"); err != nil {
+			t.Fatalf("UnlockSmartLock() returned error: %v", err)
+		}
+		if len(got) != 2 || got[0].Command != "lock" || got[1].Command != "unlock" {
+			t.Errorf("commands = %+v; want lock, unlock", got)
+		}
+	})
+
+	t.Run("Deadbolt", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, lockHandler(t, DeviceTypeSmartLockPro, LockStateLocked, &got))
+
+		if err := client.DeadboltSmartLockPro(context.Background(), "L1"); err != nil {
+			t.Fatalf("DeadboltSmartLockPro() returned error: %v", err)
+		}
+		if len(got) != 1 || got[0].Command != "deadbolt" {
+			t.Errorf("commands = %+v; want deadbolt", got)
+		}
+	})
+
+	t.Run("DeadboltOnBasicLock", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, lockHandler(t, DeviceTypeSmartLock, LockStateLocked, &got))
+
+		if err := client.DeadboltSmartLockPro(context.Background(), "L1"); !errors.Is(err, ErrDeviceTypeMismatch) {
+			t.Errorf("DeadboltSmartLockPro() error = %v; want ErrDeviceTypeMismatch", err)
+		}
+		if err := client.DeadboltSmartLockPro(context.Background(), "X1"); !errors.Is(err, ErrDeviceNotInList) {
+			t.Errorf("DeadboltSmartLockPro(X1) error = %v; want ErrDeviceNotInList", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("commands = %+v; want none sent", got)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// The IR media helpers send the documented commands of the remote's type:
+//
+//   - IRVolumeUp, IRVolumeDown: volumeAdd, volumeSub (all media remotes)
+//   - IRChannelUp, IRChannelDown, IRSetChannel: channelAdd, channelSub, SetChannel
+//     (TV, Streamer and Set Top Box remotes)
+//   - IRMute: setMute (DVD and Speaker remotes; the TV command set has no mute)
+//
+// The remote type is read from the device list first (one extra API call). A device that is not
+// a media IR remote returns ErrDeviceTypeMismatch, a deviceID missing from the device list returns
+// ErrDeviceNotInList, and a command its type does not document returns ErrUnknownCommand. DIY
+// remotes are sent the same command name with commandType "customize", which presses the learned
+// button of that name.
+
+// mediaRemoteTypes are the IR remote types, and their DIY variants, controlled by the media helpers.
+var mediaRemoteTypes = []RemoteType{
+	RemoteTypeTV,
+	RemoteTypeStreamer,
+	RemoteTypeSetTopBox,
+	RemoteTypeDVD,
+	RemoteTypeSpeaker,
+}
+
+// IRVolumeUp raises the volume of a TV or other media IR remote.
+func (c *Client) IRVolumeUp(ctx context.Context, deviceID string) error {
+	return c.sendMediaCommand(ctx, deviceID, "volumeAdd", nil)
+}
+
+// IRVolumeDown lowers the volume of a TV or other media IR remote.
+func (c *Client) IRVolumeDown(ctx context.Context, deviceID string) error {
+	return c.sendMediaCommand(ctx, deviceID, "volumeSub", nil)
+}
+
+// IRMute toggles mute on a DVD or Speaker IR remote.
+func (c *Client) IRMute(ctx context.Context, deviceID string) error {
+	return c.sendMediaCommand(ctx, deviceID, "setMute", nil)
+}
+
+// IRChannelUp switches a TV, Streamer or Set Top Box IR remote to the next channel.
+func (c *Client) IRChannelUp(ctx context.Context, deviceID string) error {
+	return c.sendMediaCommand(ctx, deviceID, "channelAdd", nil)
+}
+
+// IRChannelDown switches a TV, Streamer or Set Top Box IR remote to the previous channel.
+func (c *Client) IRChannelDown(ctx context.Context, deviceID string) error {
+	return c.sendMediaCommand(ctx, deviceID, "channelSub", nil)
+}
+
+// IRSetChannel switches a TV, Streamer or Set Top Box IR remote to channel.
+// A channel below 1 returns ErrInvalidParameter without any API call.
+func (c *Client) IRSetChannel(ctx context.Context, deviceID string, channel int) error {
+	if channel <= 0 {
+		return fmt.Errorf("%w: channel %d must be greater than 0", ErrInvalidParameter, channel)
+	}
+	return c.sendMediaCommand(ctx, deviceID, "SetChannel", channel)
+}
+
+// sendMediaCommand verifies that deviceID is a media IR remote supporting command and sends it,
+// as a customize command for DIY remotes.
+func (c *Client) sendMediaCommand(ctx context.Context, deviceID, command string, parameter interface{}) error {
+	if deviceID == "" {
+		return fmt.Errorf("deviceID cannot be empty")
+	}
+	devices, err := c.GetDevices(ctx)
+	if err != nil {
+		return err
+	}
+	remote, ok := devices.infraredRemote(deviceID)
+	if !ok {
+		if deviceType, found := devices.deviceType(deviceID); found {
+			return fmt.Errorf("%w: device %s is %q, want a media IR remote", ErrDeviceTypeMismatch, deviceID, string(deviceType))
+		}
+		return fmt.Errorf("%w: %s", ErrDeviceNotInList, deviceID)
+	}
+	if !slices.Contains(mediaRemoteTypes, remote.Type().Base()) {
+		return fmt.Errorf("%w: device %s is %q, want one of %q", ErrDeviceTypeMismatch, deviceID, remote.RemoteType, mediaRemoteTypes)
+	}
+
+	if remote.Type().IsDIY() {
+		_, err = c.SendDeviceCommand(ctx, deviceID, command, parameter, string(CommandTypeCustomize))
+		return err
+	}
+	if err := validateCommandName(DeviceType(remote.RemoteType), command); err != nil {
+		return err
+	}
+	_, err = c.SendDeviceCommand(ctx, deviceID, command, parameter, "")
+	return err
+}
+
+// infraredRemote returns the virtual IR remote with the given ID.
+func (r *GetDevicesResponse) infraredRemote(deviceID string) (*InfraredRemoteDevice, bool) {
+	for i := range r.InfraredRemoteList {
+		if r.InfraredRemoteList[i].DeviceID == deviceID {
+			return &r.InfraredRemoteList[i], true
+		}
+	}
+	return nil, false
+}
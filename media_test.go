@@ -0,0 +1,88 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// mediaHandler serves a device list with media IR remotes for GET requests and captures commands.
+func mediaHandler(t *testing.T, got *[]capturedCommand) http.HandlerFunc {
+	capture := commandCaptureHandler(t, got)
+	devices := statusHandler(`{
+		"deviceList": [{"deviceId": "B1", "deviceType": "Bot"}],
+		"infraredRemoteList": [
+			{"deviceId": "TV1", "remoteType": "TV"},
+			{"deviceId": "TV2", "remoteType": "DIY TV"},
+			{"deviceId": "SP1", "remoteType": "Speaker"},
+			{"deviceId": "AC1", "remoteType": "Air Conditioner"}
+		]
+	}`)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			devices(w, r)
+			return
+		}
+		capture(w, r)
+	}
+}
+
+func TestIRMediaCommands(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Commands", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, mediaHandler(t, &got))
+
+		calls := []struct {
+			name string
+			call func() error
+			want capturedCommand
+		}{
+			{"IRVolumeUp", func() error { return client.IRVolumeUp(ctx, "TV1") }, capturedCommand{Command: "volumeAdd", CommandType: "command", Parameter: "default"}},
+			{"IRVolumeDown", func() error { return client.IRVolumeDown(ctx, "SP1") }, capturedCommand{Command: "volumeSub", CommandType: "command", Parameter: "default"}},
+			{"IRMute", func() error { return client.IRMute(ctx, "SP1") }, capturedCommand{Command: "setMute", CommandType: "command", Parameter: "default"}},
+			{"IRChannelUp", func() error { return client.IRChannelUp(ctx, "TV1") }, capturedCommand{Command: "channelAdd", CommandType: "command", Parameter: "default"}},
+			{"IRChannelDown", func() error { return client.IRChannelDown(ctx, "TV1") }, capturedCommand{Command: "channelSub", CommandType: "command", Parameter: "default"}},
+			{"IRSetChannel", func() error { return client.IRSetChannel(ctx, "TV1", 15) }, capturedCommand{Command: "SetChannel", CommandType: "command", Parameter: float64(15)}},
+			{"DIY", func() error { return client.IRVolumeUp(ctx, "TV2") }, capturedCommand{Command: "volumeAdd", CommandType: "customize", Parameter: "default"}},
+		}
+		for _, tc := range calls {
+			got = nil
+			if err := tc.call(); err != nil {
+				t.Errorf("%s returned error: %v", tc.name, err)
+				continue
+			}
+			if len(got) != 1 || got[0].Command != tc.want.Command || got[0].CommandType != tc.want.CommandType || got[0].Parameter != tc.want.Parameter {
+				t.Errorf("%s sent %+v; want %+v", tc.name, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var got []capturedCommand
+		client, _ := setupMockServer(t, mediaHandler(t, &got))
+
+		if err := client.IRSetChannel(ctx, "TV1", 0); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("IRSetChannel(0) error = %v; want ErrInvalidParameter", err)
+		}
+		if err := client.IRMute(ctx, "TV1"); !errors.Is(err, ErrUnknownCommand) {
+			t.Errorf("IRMute(TV) error = %v; want ErrUnknownCommand", err)
+		}
+		if err := client.IRChannelUp(ctx, "SP1"); !errors.Is(err, ErrUnknownCommand) {
+			t.Errorf("IRChannelUp(Speaker) error = %v; want ErrUnknownCommand", err)
+		}
+		for _, id := range []string{"AC1", "B1"} {
+			if err := client.IRVolumeUp(ctx, id); !errors.Is(err, ErrDeviceTypeMismatch) {
+				t.Errorf("IRVolumeUp(%s) error = %v; want ErrDeviceTypeMismatch", id, err)
+			}
+		}
+		if err := client.IRVolumeUp(ctx, "X1"); !errors.Is(err, ErrDeviceNotInList) {
+			t.Errorf("IRVolumeUp(X1) error = %v; want ErrDeviceNotInList", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("commands = %+v; want none sent", got)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// celsiusToFahrenheit converts a Celsius temperature to Fahrenheit.
+func celsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// MeterStatus represents the status of a Meter, Meter Plus, Meter Pro or Outdoor Meter.
+type MeterStatus struct {
+	reportedStatus // Provides ReportedAt
+
+	DeviceID    string  `json:"deviceId"`
+	DeviceType  string  `json:"deviceType"`
+	HubDeviceID string  `json:"hubDeviceId"`
+	Version     string  `json:"version"`
+	Temperature float64 `json:"temperature"` // Celsius
+	Humidity    FlexInt `json:"humidity"`    // Percentage (0-100)
+	Battery     FlexInt `json:"battery"`     // Percentage (0-100)
+	_           struct{}
+}
+
+// TemperatureF returns the temperature in Fahrenheit. Temperature (Celsius) remains the reported value.
+func (s *MeterStatus) TemperatureF() float64 {
+	return celsiusToFahrenheit(s.Temperature)
+}
+
+// GetMeterStatus retrieves the typed status of a Meter, Meter Plus, Meter Pro or Outdoor Meter.
+// Returns ErrDeviceTypeMismatch for any other device type.
+func (c *Client) GetMeterStatus(ctx context.Context, deviceID string) (*MeterStatus, error) {
+	var status MeterStatus
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, DeviceTypeMeter, DeviceTypeMeterPlus, DeviceTypeMeterPro, DeviceTypeOutdoorMeter); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Hub2Status represents the status of a Hub 2, which has a built-in thermo-hygrometer and light sensor.
+type Hub2Status struct {
+	reportedStatus // Provides ReportedAt
+
+	DeviceID    string  `json:"deviceId"`
+	DeviceType  string  `json:"deviceType"`
+	HubDeviceID string  `json:"hubDeviceId"`
+	Version     string  `json:"version"`
+	Temperature float64 `json:"temperature"` // Celsius
+	Humidity    FlexInt `json:"humidity"`    // Percentage (0-100)
+	LightLevel  FlexInt `json:"lightLevel"`  // 1-20
+	_           struct{}
+}
+
+// TemperatureF returns the temperature in Fahrenheit. Temperature (Celsius) remains the reported value.
+func (s *Hub2Status) TemperatureF() float64 {
+	return celsiusToFahrenheit(s.Temperature)
+}
+
+// GetHub2Status retrieves the typed status of a Hub 2.
+// Returns ErrDeviceTypeMismatch if the device is not a Hub 2.
+func (c *Client) GetHub2Status(ctx context.Context, deviceID string) (*Hub2Status, error) {
+	var status Hub2Status
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, DeviceTypeHub2); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// co2FieldNames lists the keys under which meters report the CO2 concentration.
+// The Meter Pro (CO2) uses "CO2"; the CO2 Meter reports it in lower case.
+var co2FieldNames = []string{"CO2", "co2"}
+
+// CO2MeterStatus represents the status of a Meter Pro, Meter Pro (CO2) or CO2 Meter.
+// All variants are decoded into the same structure; use IsMeterPro to tell them apart.
+type CO2MeterStatus struct {
+	reportedStatus // Provides ReportedAt
+
+	DeviceID    string  `json:"deviceId"`
+	DeviceType  string  `json:"deviceType"`
+	HubDeviceID string  `json:"hubDeviceId"`
+	Version     string  `json:"version"`
+	Temperature float64 `json:"temperature"` // Celsius
+	Humidity    FlexInt `json:"humidity"`    // Percentage (0-100)
+	CO2         FlexInt `json:"CO2"`         // ppm
+	Battery     FlexInt `json:"battery"`     // Percentage (0-100)
+	_           struct{}
+}
+
+// UnmarshalJSON decodes either meter variant, accepting all known CO2 field names.
+func (s *CO2MeterStatus) UnmarshalJSON(data []byte) error {
+	type plain CO2MeterStatus
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, name := range co2FieldNames {
+		raw, ok := fields[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, &decoded.CO2); err != nil {
+			return fmt.Errorf("invalid %s value %s: %w", name, string(raw), err)
+		}
+		break
+	}
+
+	*s = CO2MeterStatus(decoded)
+	return nil
+}
+
+// IsMeterPro reports whether the status was read from a Meter Pro, with or without CO2.
+func (s *CO2MeterStatus) IsMeterPro() bool {
+	return s.DeviceType == DeviceTypeMeterPro || s.DeviceType == DeviceTypeMeterProCO2
+}
+
+// CO2 concentration bands used by CO2Level, in ppm.
+const (
+	CO2ModerateThreshold = 1000 // From this level ventilation is advisable
+	CO2PoorThreshold     = 1500 // From this level air quality is poor
+)
+
+// CO2Level classifies the CO2 concentration as "good" (below 1000 ppm), "moderate"
+// (1000-1499 ppm) or "poor" (1500 ppm and above). It returns "unknown" if no CO2 reading
+// was reported, e.g. for a Meter Pro without the CO2 sensor.
+func (s *CO2MeterStatus) CO2Level() string {
+	switch {
+	case s.CO2 <= 0:
+		return "unknown"
+	case s.CO2 < CO2ModerateThreshold:
+		return "good"
+	case s.CO2 < CO2PoorThreshold:
+		return "moderate"
+	}
+	return "poor"
+}
+
+// GetCO2MeterStatus retrieves the typed status of a Meter Pro, Meter Pro (CO2) or CO2 Meter.
+// A plain Meter Pro reports no CO2 concentration, so CO2 is left zero and CO2Level returns "unknown".
+// Returns ErrDeviceTypeMismatch for any other device type.
+func (c *Client) GetCO2MeterStatus(ctx context.Context, deviceID string) (*CO2MeterStatus, error) {
+	var status CO2MeterStatus
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, DeviceTypeMeterPro, DeviceTypeMeterProCO2, DeviceTypeCO2Meter); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
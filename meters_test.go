@@ -0,0 +1,118 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetCO2MeterStatus(t *testing.T) {
+	testCases := []struct {
+		name         string
+		body         string
+		wantMeterPro bool
+		wantCO2      FlexInt
+	}{
+		{
+			name:         "MeterPro",
+			body:         `{"deviceId": "P1", "deviceType": "MeterPro(CO2)", "hubDeviceId": "P1", "temperature": 22.4, "humidity": 48, "CO2": 812, "battery": 100, "version": "V1.0"}`,
+			wantMeterPro: true,
+			wantCO2:      812,
+		},
+		{
+			name:         "MeterProWithoutCO2",
+			body:         `{"deviceId": "P2", "deviceType": "MeterPro", "temperature": 20.0, "humidity": 50, "battery": 80}`,
+			wantMeterPro: true,
+			wantCO2:      0,
+		},
+		{
+			name:         "CO2Meter",
+			body:         `{"deviceId": "C1", "deviceType": "CO2 Meter", "hubDeviceId": "H1", "temperature": 21.0, "humidity": 55, "co2": 640, "battery": 90, "version": "V2.1"}`,
+			wantMeterPro: false,
+			wantCO2:      640,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := setupMockServer(t, statusHandler(tc.body))
+
+			status, err := client.GetCO2MeterStatus(context.Background(), "X")
+			if err != nil {
+				t.Fatalf("GetCO2MeterStatus() returned error: %v", err)
+			}
+			if status.IsMeterPro() != tc.wantMeterPro {
+				t.Errorf("IsMeterPro() = %v; want %v", status.IsMeterPro(), tc.wantMeterPro)
+			}
+			if status.CO2 != tc.wantCO2 {
+				t.Errorf("CO2 = %d; want %d", status.CO2, tc.wantCO2)
+			}
+			if status.Temperature == 0 || status.Humidity == 0 {
+				t.Errorf("Temperature/Humidity not decoded: %+v", *status)
+			}
+		})
+	}
+
+	t.Run("DeviceTypeMismatch", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(`{"deviceId": "M1", "deviceType": "Meter", "temperature": 20.1, "humidity": 40}`))
+
+		_, err := client.GetCO2MeterStatus(context.Background(), "M1")
+		if !errors.Is(err, ErrDeviceTypeMismatch) {
+			t.Errorf("GetCO2MeterStatus() error = %v; want ErrDeviceTypeMismatch", err)
+		}
+	})
+}
+
+func TestCO2MeterStatus_CO2Level(t *testing.T) {
+	testCases := []struct {
+		co2  FlexInt
+		want string
+	}{
+		{0, "unknown"},
+		{420, "good"},
+		{999, "good"},
+		{1000, "moderate"},
+		{1499, "moderate"},
+		{1500, "poor"},
+		{3000, "poor"},
+	}
+	for _, tc := range testCases {
+		status := CO2MeterStatus{CO2: tc.co2}
+		if got := status.CO2Level(); got != tc.want {
+			t.Errorf("CO2Level() for %d ppm = %q; want %q", tc.co2, got, tc.want)
+		}
+	}
+}
+
+func TestGetMeterStatus(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(`{"deviceId": "M1", "deviceType": "MeterPlus", "hubDeviceId": "H1", "temperature": 25.0, "humidity": 40, "battery": 88, "version": "V3.3"}`))
+
+	status, err := client.GetMeterStatus(context.Background(), "M1")
+	if err != nil {
+		t.Fatalf("GetMeterStatus() returned error: %v", err)
+	}
+	if status.Temperature != 25.0 {
+		t.Errorf("Temperature = %v; want 25.0", status.Temperature)
+	}
+	if got := status.TemperatureF(); got != 77.0 {
+		t.Errorf("TemperatureF() = %v; want 77.0", got)
+	}
+	if status.Humidity != 40 || status.Battery != 88 {
+		t.Errorf("Humidity/Battery not decoded: %+v", *status)
+	}
+}
+
+func TestGetHub2Status(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(`{"deviceId": "H2", "deviceType": "Hub 2", "hubDeviceId": "H2", "temperature": -40.0, "humidity": 50, "lightLevel": 12, "version": "V1.0"}`))
+
+	status, err := client.GetHub2Status(context.Background(), "H2")
+	if err != nil {
+		t.Fatalf("GetHub2Status() returned error: %v", err)
+	}
+	if got := status.TemperatureF(); got != -40.0 {
+		t.Errorf("TemperatureF() = %v; want -40.0", got)
+	}
+	if status.LightLevel != 12 {
+		t.Errorf("LightLevel = %d; want 12", status.LightLevel)
+	}
+}
@@ -0,0 +1,65 @@
+package switchbot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MetricEvent describes a single HTTP attempt made by the client.
+type MetricEvent struct {
+	Method     string        // HTTP method
+	Path       string        // Request path, e.g. "/v1.1/devices/ABC/status"; high-cardinality, as it contains IDs
+	Route      string        // Path with IDs templated, e.g. "/v1.1/devices/{id}/status"; use this as a metric label
+	HTTPStatus int           // HTTP status code; 0 if no response was received
+	APIStatus  int           // SwitchBot statusCode; 0 if the body carried none
+	Duration   time.Duration // From sending the request until the body was read
+	Err        error         // Error returned for the attempt, if any
+	_          struct{}
+}
+
+// WithMetrics registers a callback invoked after every HTTP attempt, including each retry made
+// by helpers such as CommandQueue. Use it to feed counters and latency histograms (e.g. Prometheus).
+// The callback runs synchronously on the request goroutine, so it should return quickly.
+func WithMetrics(fn func(MetricEvent)) ClientOption {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("metrics callback cannot be nil")
+		}
+		c.metrics = fn
+		return nil
+	}
+}
+
+// emitMetric reports the outcome of one attempt to the metrics callback.
+func (c *Client) emitMetric(method, path string, httpStatus int, resp *Response, elapsed time.Duration, err error) {
+	event := MetricEvent{
+		Method:     method,
+		Path:       path,
+		Route:      routeTemplate(path),
+		HTTPStatus: httpStatus,
+		Duration:   elapsed,
+		Err:        err,
+	}
+	if resp != nil {
+		event.APIStatus = resp.StatusCode
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		event.APIStatus = apiErr.StatusCode
+	}
+	c.metrics(event)
+}
+
+// routeTemplate replaces the device or scene ID in path with "{id}" and drops any query, e.g.
+// "/v1.1/devices/ABC/status" becomes "/v1.1/devices/{id}/status".
+func routeTemplate(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(path, "/")
+	// segments are "", version, collection, id, action...
+	if len(segments) > 3 && (segments[2] == "devices" || segments[2] == "scenes") && segments[3] != "" {
+		segments[3] = "{id}"
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,84 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithMetrics(t *testing.T) {
+	_, server := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "OFFLINE") {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"statusCode": 161, "message": "device offline", "body": {}}`)
+			return
+		}
+		statusHandler(`{"deviceId": "D1"}`)(w, r)
+	})
+
+	var events []MetricEvent
+	client, err := NewClient("token", "secret", WithBaseURL(server.URL), WithMetrics(func(e MetricEvent) {
+		events = append(events, e)
+	}))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	if _, err := client.GetDeviceStatus(context.Background(), "D1"); err != nil {
+		t.Fatalf("GetDeviceStatus() returned error: %v", err)
+	}
+	if _, err := client.SendDeviceCommand(context.Background(), "OFFLINE", "turnOn", nil, ""); !errors.Is(err, ErrDeviceOffline) {
+		t.Fatalf("SendDeviceCommand() error = %v; want ErrDeviceOffline", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("got %d metric events; want 2", len(events))
+	}
+	ok := events[0]
+	if ok.Method != http.MethodGet || ok.Path != "/v1.1/devices/D1/status" || ok.Route != "/v1.1/devices/{id}/status" || ok.HTTPStatus != 200 || ok.APIStatus != 100 || ok.Err != nil || ok.Duration <= 0 {
+		t.Errorf("success event = %+v", ok)
+	}
+	failed := events[1]
+	if failed.Method != http.MethodPost || failed.Route != "/v1.1/devices/{id}/commands" || failed.HTTPStatus != 200 || failed.APIStatus != 161 || !errors.Is(failed.Err, ErrDeviceOffline) {
+		t.Errorf("failure event = %+v", failed)
+	}
+}
+
+func TestWithMetrics_NetworkError(t *testing.T) {
+	var events []MetricEvent
+	client, err := NewClient("token", "secret", WithBaseURL("http://127.0.0.1:1"), WithMetrics(func(e MetricEvent) {
+		events = append(events, e)
+	}))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	if _, err := client.GetDevices(context.Background()); err == nil {
+		t.Fatal("GetDevices() returned nil error")
+	}
+	if len(events) != 1 || events[0].HTTPStatus != 0 || events[0].Err == nil {
+		t.Errorf("events = %+v; want one event without HTTP status", events)
+	}
+}
+
+func TestRouteTemplate(t *testing.T) {
+	testCases := []struct {
+		path string
+		want string
+	}{
+		{"/v1.1/devices", "/v1.1/devices"},
+		{"/v1.1/devices/ABC/status", "/v1.1/devices/{id}/status"},
+		{"/v1.1/devices/ABC/commands", "/v1.1/devices/{id}/commands"},
+		{"/v1.1/scenes/S1/execute", "/v1.1/scenes/{id}/execute"},
+		{"/v1.1/webhook/queryWebhook", "/v1.1/webhook/queryWebhook"},
+		{"/v1.1/devices/ABC/status?foo=bar", "/v1.1/devices/{id}/status"},
+	}
+	for _, tc := range testCases {
+		if got := routeTemplate(tc.path); got != tc.want {
+			t.Errorf("routeTemplate(%q) = %q; want %q", tc.path, got, tc.want)
+		}
+	}
+}
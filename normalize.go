@@ -0,0 +1,119 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NormalizedStatus is a device status converted to consistent units regardless of device type.
+// Fields a device does not report are nil (or the zero time for SampledAt).
+type NormalizedStatus struct {
+	DeviceID    string
+	DeviceType  string
+	Temperature *float64  // Celsius
+	Humidity    *int      // Percentage (0-100)
+	Brightness  *int      // 0-100, from light brightness, Hub 2 lightLevel or sensor bright/dim
+	Battery     *int      // Percentage (0-100)
+	SampledAt   time.Time // When the reading was taken, if the device reports it
+	Raw         DeviceStatus
+	_           struct{}
+}
+
+// hub2MaxLightLevel is the top of the Hub 2 lightLevel scale (1-20).
+const hub2MaxLightLevel = 20
+
+// GetDeviceStatusNormalized retrieves a device status and normalizes it with NormalizeDeviceStatus.
+func (c *Client) GetDeviceStatusNormalized(ctx context.Context, deviceID string) (*NormalizedStatus, error) {
+	status, err := c.GetDeviceStatus(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	return NormalizeDeviceStatus(status), nil
+}
+
+// NormalizeDeviceStatus converts a raw device status to SI-style units:
+//   - temperatures are Celsius, converting devices that report temperatureUnit "F";
+//   - brightness is 0-100, scaling the Hub 2 lightLevel and mapping sensor bright/dim to 100/0;
+//   - timestamps (timeOfSample, lastUpdateTime) are time.Time, accepting seconds or milliseconds.
+func NormalizeDeviceStatus(status DeviceStatus) *NormalizedStatus {
+	n := &NormalizedStatus{Raw: status}
+	n.DeviceID, _ = status["deviceId"].(string)
+	n.DeviceType, _ = status["deviceType"].(string)
+
+	if t, ok := statusFloat(status["temperature"]); ok {
+		if unit, _ := status["temperatureUnit"].(string); strings.EqualFold(unit, "F") {
+			t = (t - 32) * 5 / 9
+		}
+		n.Temperature = &t
+	}
+	if h, ok := statusInt(status["humidity"]); ok {
+		n.Humidity = &h
+	}
+	if b, ok := status.Battery(); ok {
+		n.Battery = &b
+	}
+
+	switch n.DeviceType {
+	case DeviceTypeHub2:
+		if level, ok := statusInt(status["lightLevel"]); ok {
+			b := level * 100 / hub2MaxLightLevel
+			n.Brightness = &b
+		}
+	default:
+		switch v := status["brightness"].(type) {
+		case string:
+			switch Brightness(v) {
+			case BrightnessBright:
+				b := 100
+				n.Brightness = &b
+			case BrightnessDim:
+				b := 0
+				n.Brightness = &b
+			default:
+				if b, ok := statusInt(v); ok {
+					n.Brightness = &b
+				}
+			}
+		default:
+			if b, ok := statusInt(v); ok {
+				n.Brightness = &b
+			}
+		}
+	}
+
+	n.SampledAt = reportedTime(status)
+	return n
+}
+
+// epochToTime converts a Unix timestamp in seconds or milliseconds to time.Time.
+func epochToTime(ts int64) time.Time {
+	// Millisecond timestamps exceed 1e12 for any date after 2001.
+	if ts >= 1e12 {
+		return time.UnixMilli(ts)
+	}
+	return time.Unix(ts, 0)
+}
+
+// statusFloat reads a number that may be reported as a JSON number or a numeric string.
+func statusFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// statusInt is statusFloat truncated to an int.
+func statusInt(v interface{}) (int, bool) {
+	f, ok := statusFloat(v)
+	return int(f), ok
+}
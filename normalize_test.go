@@ -0,0 +1,105 @@
+package switchbot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetDeviceStatusNormalized(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	floatPtr := func(f float64) *float64 { return &f }
+
+	testCases := []struct {
+		name            string
+		body            string
+		wantTemperature *float64
+		wantHumidity    *int
+		wantBrightness  *int
+		wantBattery     *int
+		wantSampledAt   time.Time
+	}{
+		{
+			name:            "MeterStringBattery",
+			body:            `{"deviceId": "M1", "deviceType": "Meter", "temperature": 21.5, "humidity": 40, "battery": "85"}`,
+			wantTemperature: floatPtr(21.5),
+			wantHumidity:    intPtr(40),
+			wantBattery:     intPtr(85),
+		},
+		{
+			name:            "FahrenheitReading",
+			body:            `{"deviceId": "M2", "deviceType": "WoIOSensor", "temperature": 77, "temperatureUnit": "F", "humidity": 50}`,
+			wantTemperature: floatPtr(25),
+			wantHumidity:    intPtr(50),
+		},
+		{
+			name:            "Hub2LightLevel",
+			body:            `{"deviceId": "H2", "deviceType": "Hub 2", "temperature": 20, "humidity": 45, "lightLevel": 10}`,
+			wantTemperature: floatPtr(20),
+			wantHumidity:    intPtr(45),
+			wantBrightness:  intPtr(50),
+		},
+		{
+			name:           "MotionSensorBright",
+			body:           `{"deviceId": "S1", "deviceType": "Motion Sensor", "brightness": "bright", "battery": 90}`,
+			wantBrightness: intPtr(100),
+			wantBattery:    intPtr(90),
+		},
+		{
+			name:           "ContactSensorDim",
+			body:           `{"deviceId": "S2", "deviceType": "Contact Sensor", "brightness": "dim"}`,
+			wantBrightness: intPtr(0),
+		},
+		{
+			name:           "ColorBulb",
+			body:           `{"deviceId": "L1", "deviceType": "Color Bulb", "power": "on", "brightness": 75}`,
+			wantBrightness: intPtr(75),
+		},
+		{
+			name:          "MillisecondTimestamp",
+			body:          `{"deviceId": "X1", "deviceType": "Bot", "timeOfSample": 1700000000000}`,
+			wantSampledAt: time.UnixMilli(1700000000000),
+		},
+		{
+			name:          "SecondTimestamp",
+			body:          `{"deviceId": "X2", "deviceType": "Bot", "lastUpdateTime": 1700000000}`,
+			wantSampledAt: time.Unix(1700000000, 0),
+		},
+	}
+
+	checkInt := func(t *testing.T, field string, got, want *int) {
+		t.Helper()
+		if (got == nil) != (want == nil) || (got != nil && *got != *want) {
+			t.Errorf("%s = %v; want %v", field, deref(got), deref(want))
+		}
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := setupMockServer(t, statusHandler(tc.body))
+
+			status, err := client.GetDeviceStatusNormalized(context.Background(), "X")
+			if err != nil {
+				t.Fatalf("GetDeviceStatusNormalized() returned error: %v", err)
+			}
+			if (status.Temperature == nil) != (tc.wantTemperature == nil) ||
+				(status.Temperature != nil && *status.Temperature != *tc.wantTemperature) {
+				t.Errorf("Temperature = %v; want %v", deref(status.Temperature), deref(tc.wantTemperature))
+			}
+			checkInt(t, "Humidity", status.Humidity, tc.wantHumidity)
+			checkInt(t, "Brightness", status.Brightness, tc.wantBrightness)
+			checkInt(t, "Battery", status.Battery, tc.wantBattery)
+			if !status.SampledAt.Equal(tc.wantSampledAt) {
+				t.Errorf("SampledAt = %v; want %v", status.SampledAt, tc.wantSampledAt)
+			}
+		})
+	}
+}
+
+// deref formats an optional value for test output.
+func deref[T any](p *T) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
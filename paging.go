@@ -0,0 +1,84 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strconv"
+)
+
+// PageOptions selects a page of physical devices for GetDevicesPaged.
+type PageOptions struct {
+	Limit  int    // Maximum devices per page; 0 returns all remaining devices
+	Cursor string // NextCursor from the previous page; empty for the first page
+	_      struct{}
+}
+
+// DevicesPage is one page of physical devices returned by GetDevicesPaged.
+type DevicesPage struct {
+	Devices    []Device
+	NextCursor string // Pass as PageOptions.Cursor to fetch the next page; empty on the last page
+	_          struct{}
+}
+
+// GetDevicesPaged returns a page of the physical devices in DeviceList.
+//
+// The v1.1 API has no pagination, so each call fetches the full device list and slices it
+// client-side; the cursor is an opaque offset into that list. Devices added or removed between
+// calls may therefore shift pages. A malformed cursor or a negative limit returns an error.
+func (c *Client) GetDevicesPaged(ctx context.Context, opts PageOptions) (*DevicesPage, error) {
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("page limit cannot be negative, got %d", opts.Limit)
+	}
+	offset := 0
+	if opts.Cursor != "" {
+		n, err := strconv.Atoi(opts.Cursor)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid page cursor %q", opts.Cursor)
+		}
+		offset = n
+	}
+
+	resp, err := c.GetDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &DevicesPage{}
+	if offset >= len(resp.DeviceList) {
+		return page, nil
+	}
+	end := len(resp.DeviceList)
+	if opts.Limit > 0 && offset+opts.Limit < end {
+		end = offset + opts.Limit
+		page.NextCursor = strconv.Itoa(end)
+	}
+	page.Devices = resp.DeviceList[offset:end]
+	return page, nil
+}
+
+// IterateDevices yields the physical devices in DeviceList one at a time:
+//
+//	for device, err := range client.IterateDevices(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(device["deviceName"])
+//	}
+//
+// The list is fetched once when iteration starts. If the request fails, a single nil device is
+// yielded with the error.
+func (c *Client) IterateDevices(ctx context.Context) iter.Seq2[Device, error] {
+	return func(yield func(Device, error) bool) {
+		resp, err := c.GetDevices(ctx)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, device := range resp.DeviceList {
+			if !yield(device, nil) {
+				return
+			}
+		}
+	}
+}
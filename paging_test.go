@@ -0,0 +1,79 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+const pagingDevicesBody = `{"deviceList": [{"deviceId": "D1"}, {"deviceId": "D2"}, {"deviceId": "D3"}], "infraredRemoteList": []}`
+
+func TestGetDevicesPaged(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(pagingDevicesBody))
+	ctx := context.Background()
+
+	var ids []interface{}
+	opts := PageOptions{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("GetDevicesPaged() did not terminate")
+		}
+		page, err := client.GetDevicesPaged(ctx, opts)
+		if err != nil {
+			t.Fatalf("GetDevicesPaged() returned error: %v", err)
+		}
+		for _, d := range page.Devices {
+			ids = append(ids, d["deviceId"])
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	if len(ids) != 3 || ids[0] != "D1" || ids[2] != "D3" {
+		t.Errorf("paged device IDs = %v; want D1, D2, D3", ids)
+	}
+
+	page, err := client.GetDevicesPaged(ctx, PageOptions{})
+	if err != nil || len(page.Devices) != 3 || page.NextCursor != "" {
+		t.Errorf("GetDevicesPaged() without a limit = %+v, %v; want all devices on one page", page, err)
+	}
+	if _, err := client.GetDevicesPaged(ctx, PageOptions{Cursor: "bogus"}); err == nil {
+		t.Error("GetDevicesPaged() with a malformed cursor did not return an error")
+	}
+	if _, err := client.GetDevicesPaged(ctx, PageOptions{Limit: -1}); err == nil {
+		t.Error("GetDevicesPaged() with a negative limit did not return an error")
+	}
+}
+
+func TestIterateDevices(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(pagingDevicesBody))
+
+	var ids []interface{}
+	for device, err := range client.IterateDevices(context.Background()) {
+		if err != nil {
+			t.Fatalf("IterateDevices() yielded error: %v", err)
+		}
+		ids = append(ids, device["deviceId"])
+		if len(ids) == 2 {
+			break
+		}
+	}
+	if len(ids) != 2 || ids[1] != "D2" {
+		t.Errorf("iterated device IDs = %v; want D1, D2", ids)
+	}
+
+	client, _ = setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	n := 0
+	for device, err := range client.IterateDevices(context.Background()) {
+		n++
+		if err == nil || device != nil {
+			t.Errorf("IterateDevices() yielded %v, %v; want a nil device and an error", device, err)
+		}
+	}
+	if n != 1 {
+		t.Errorf("IterateDevices() yielded %d times on failure; want 1", n)
+	}
+}
@@ -0,0 +1,62 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is delivered by background workers (PollDeviceStatus, StreamDeviceStatuses,
+// CommandQueue.Run) in place of a panic raised by user code they call, such as interceptors,
+// metrics callbacks, credentials providers, queue stores or error callbacks.
+type PanicError struct {
+	Value any    // The value passed to panic
+	Stack []byte // Stack trace of the panicking goroutine
+	_     struct{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// WithPanicHandler sets a function called with the recovered value whenever a background
+// worker recovers from a panic. The worker also reports the panic as a *PanicError and keeps running.
+func WithPanicHandler(handler func(recovered any)) ClientOption {
+	return func(c *Client) error {
+		if handler == nil {
+			return fmt.Errorf("panic handler cannot be nil")
+		}
+		c.panicHandler = handler
+		return nil
+	}
+}
+
+// protect runs f, converting a panic into a *PanicError and notifying the panic handler.
+func (c *Client) protect(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+			if c.panicHandler != nil {
+				c.panicHandler(r)
+			}
+		}
+	}()
+	return f()
+}
+
+// getDeviceStatusProtected is GetDeviceStatus for background workers: panics become a *PanicError.
+func (c *Client) getDeviceStatusProtected(ctx context.Context, deviceID string) (DeviceStatus, error) {
+	var status DeviceStatus
+	err := c.protect(func() error {
+		var err error
+		status, err = c.GetDeviceStatus(ctx, deviceID)
+		return err
+	})
+	return status, err
+}
@@ -0,0 +1,104 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPanicRecovery_PollDeviceStatus(t *testing.T) {
+	_, server := setupMockServer(t, statusHandler(`{"deviceId": "D1"}`))
+
+	var calls int32
+	var mu sync.Mutex
+	var recovered []any
+	noSleep := func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	client, err := NewClient("token", "secret", WithBaseURL(server.URL), WithSleeper(noSleep),
+		WithRequestInterceptor(func(*http.Request) error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				panic("interceptor bug")
+			}
+			return nil
+		}),
+		WithPanicHandler(func(r any) {
+			mu.Lock()
+			recovered = append(recovered, r)
+			mu.Unlock()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := client.PollDeviceStatus(ctx, "D1", PollConfig{Interval: time.Millisecond})
+
+	first := <-results
+	var panicErr *PanicError
+	if !errors.As(first.Err, &panicErr) || panicErr.Value != "interceptor bug" || len(panicErr.Stack) == 0 {
+		t.Fatalf("first result error = %v; want *PanicError", first.Err)
+	}
+	second := <-results
+	if second.Err != nil || second.Status["deviceId"] != "D1" {
+		t.Errorf("second result = %+v; want the poller to keep running", second)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(recovered) != 1 || recovered[0] != "interceptor bug" {
+		t.Errorf("panic handler got %v; want one call with the panic value", recovered)
+	}
+}
+
+// panickyStore panics on the first Load and then behaves like memoryQueueStore.
+type panickyStore struct {
+	*memoryQueueStore
+	loads int32
+}
+
+func (s *panickyStore) Load(ctx context.Context) ([]QueuedCommand, error) {
+	if atomic.AddInt32(&s.loads, 1) == 1 {
+		panic("store bug")
+	}
+	return s.memoryQueueStore.Load(ctx)
+}
+
+func TestPanicRecovery_CommandQueueRun(t *testing.T) {
+	var got []capturedCommand
+	store := &panickyStore{memoryQueueStore: newMemoryQueueStore()}
+	ctx, cancel := context.WithCancel(context.Background())
+	// Stop Run once the store has been drained a few times
+	sleep := func(ctx context.Context, d time.Duration) error {
+		if atomic.LoadInt32(&store.loads) >= 3 {
+			cancel()
+		}
+		return ctx.Err()
+	}
+	var handled int32
+	queue := newTestQueue(t, commandCaptureHandler(t, &got), store,
+		WithPanicHandler(func(any) { atomic.AddInt32(&handled, 1) }), WithSleeper(sleep))
+
+	var delivered []error
+
+	queue.Run(ctx, time.Millisecond, func(err error) {
+		delivered = append(delivered, err)
+		panic("onError bug")
+	})
+
+	if atomic.LoadInt32(&store.loads) < 3 {
+		t.Errorf("store loaded %d times; want Run to keep draining after panics", store.loads)
+	}
+	var panicErr *PanicError
+	if len(delivered) != 1 || !errors.As(delivered[0], &panicErr) || panicErr.Value != "store bug" {
+		t.Errorf("onError got %v; want one *PanicError from the store", delivered)
+	}
+	// Both the store panic and the onError panic reach the handler.
+	if got := atomic.LoadInt32(&handled); got != 2 {
+		t.Errorf("panic handler called %d times; want 2", got)
+	}
+}
@@ -0,0 +1,77 @@
+package switchbot
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+const defaultPollInterval = time.Minute
+
+// Sleeper pauses for d, returning early with ctx.Err() if ctx is done first.
+type Sleeper func(ctx context.Context, d time.Duration) error
+
+// sleepContext is the default Sleeper, backed by a timer.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PollConfig configures PollDeviceStatus.
+type PollConfig struct {
+	Interval time.Duration // Delay between polls; defaults to one minute
+	// InitialJitter, if positive, delays the first poll by a random duration in [0, InitialJitter)
+	// so that many pollers started together do not hit the API in sync.
+	InitialJitter time.Duration
+	_             struct{}
+}
+
+// jitter returns a random duration in [0, max), or 0 if max is not positive.
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max)
+}
+
+// PollDeviceStatus fetches the status of a device every cfg.Interval and delivers each result on the
+// returned channel until ctx is cancelled, at which point the channel is closed.
+// Errors are delivered as results and do not stop polling; a panic while fetching is delivered as a *PanicError.
+func (c *Client) PollDeviceStatus(ctx context.Context, deviceID string, cfg PollConfig) <-chan DeviceStatusResult {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultPollInterval
+	}
+
+	results := make(chan DeviceStatusResult)
+	go func() {
+		defer close(results)
+
+		if err := c.sleep(ctx, jitter(cfg.InitialJitter)); err != nil {
+			return
+		}
+		for {
+			status, err := c.getDeviceStatusProtected(ctx, deviceID)
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case results <- DeviceStatusResult{DeviceID: deviceID, Status: status, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err := c.sleep(ctx, cfg.Interval); err != nil {
+				return
+			}
+		}
+	}()
+	return results
+}
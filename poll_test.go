@@ -0,0 +1,83 @@
+package switchbot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSleeper returns a Sleeper that records requested durations without actually sleeping.
+func recordingSleeper(mu *sync.Mutex, durations *[]time.Duration) Sleeper {
+	return func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		*durations = append(*durations, d)
+		mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func TestPollDeviceStatus(t *testing.T) {
+	_, server := setupMockServer(t, statusHandler(`{"deviceId": "D1", "power": "on"}`))
+
+	var mu sync.Mutex
+	var sleeps []time.Duration
+	client, err := NewClient("mock-token", "mock-secret", WithBaseURL(server.URL), WithSleeper(recordingSleeper(&mu, &sleeps)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := client.PollDeviceStatus(ctx, "D1", PollConfig{Interval: 30 * time.Second})
+	for i := 0; i < 3; i++ {
+		result := <-results
+		if result.Err != nil {
+			t.Fatalf("poll %d returned error: %v", i, result.Err)
+		}
+		if result.Status["power"] != "on" {
+			t.Errorf("poll %d status = %v", i, result.Status)
+		}
+	}
+	cancel()
+	for range results {
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if sleeps[0] != 0 {
+		t.Errorf("initial delay = %v; want 0 without jitter", sleeps[0])
+	}
+	for _, d := range sleeps[1:] {
+		if d != 30*time.Second {
+			t.Errorf("poll interval = %v; want 30s", d)
+		}
+	}
+}
+
+func TestPollDeviceStatus_InitialJitter(t *testing.T) {
+	_, server := setupMockServer(t, statusHandler(`{"power": "on"}`))
+
+	const window = 10 * time.Second
+	for i := 0; i < 20; i++ {
+		var mu sync.Mutex
+		var sleeps []time.Duration
+		client, err := NewClient("mock-token", "mock-secret", WithBaseURL(server.URL), WithSleeper(recordingSleeper(&mu, &sleeps)))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		results := client.PollDeviceStatus(ctx, "D1", PollConfig{Interval: time.Minute, InitialJitter: window})
+		<-results
+		cancel()
+		for range results {
+		}
+
+		mu.Lock()
+		initial := sleeps[0]
+		mu.Unlock()
+		if initial < 0 || initial >= window {
+			t.Errorf("initial delay = %v; want within [0, %v)", initial, window)
+		}
+	}
+}
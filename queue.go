@@ -0,0 +1,184 @@
+package switchbot
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultQueueMaxAttempts = 3
+	defaultQueueRetryDelay  = 2 * time.Second
+)
+
+// QueuedCommand is a device command persisted by a CommandQueue until it has been delivered.
+type QueuedCommand struct {
+	ID          string      `json:"id"`
+	DeviceID    string      `json:"deviceId"`
+	Command     string      `json:"command"`
+	Parameter   interface{} `json:"parameter,omitempty"`
+	CommandType string      `json:"commandType,omitempty"`
+	Attempts    int         `json:"attempts"` // Delivery attempts made so far
+	EnqueuedAt  time.Time   `json:"enqueuedAt"`
+	Seq         uint64      `json:"seq"` // Enqueue order within one CommandQueue; breaks EnqueuedAt ties
+	LastError   string      `json:"lastError,omitempty"`
+	_           struct{}
+}
+
+// QueueStore persists queued commands so they survive restarts.
+// Save must insert or replace the command with the same ID.
+type QueueStore interface {
+	Save(ctx context.Context, cmd QueuedCommand) error
+	Load(ctx context.Context) ([]QueuedCommand, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// QueueConfig configures a CommandQueue. Zero values fall back to the defaults (3 attempts, 2s delay).
+type QueueConfig struct {
+	MaxAttempts int           // Attempts per command in a single Drain before leaving it for the next one
+	RetryDelay  time.Duration // Delay between attempts
+	_           struct{}
+}
+
+// CommandQueue delivers device commands at least once: commands are persisted in a QueueStore
+// before sending and only removed after the API accepts them. A command may be sent more than
+// once if the process stops between delivery and deletion.
+type CommandQueue struct {
+	client *Client
+	store  QueueStore
+	config QueueConfig
+
+	// drainMu prevents concurrent drains from sending the same command twice.
+	drainMu sync.Mutex
+	seq     atomic.Uint64
+}
+
+// NewCommandQueue creates a CommandQueue that sends commands through client and persists them in store.
+func NewCommandQueue(client *Client, store QueueStore, config QueueConfig) (*CommandQueue, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client cannot be nil")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("store cannot be nil")
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultQueueMaxAttempts
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = defaultQueueRetryDelay
+	}
+	return &CommandQueue{client: client, store: store, config: config}, nil
+}
+
+// Enqueue persists a command for delivery and returns its queue ID. The command is sent by the next Drain.
+func (q *CommandQueue) Enqueue(ctx context.Context, deviceID, command string, parameter interface{}, commandType string) (string, error) {
+	if deviceID == "" {
+		return "", fmt.Errorf("deviceID cannot be empty")
+	}
+	if command == "" {
+		return "", fmt.Errorf("command cannot be empty")
+	}
+	id, err := getUUIDv7String()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate queue ID: %w", err)
+	}
+	cmd := QueuedCommand{
+		ID:          id,
+		DeviceID:    deviceID,
+		Command:     command,
+		Parameter:   parameter,
+		CommandType: commandType,
+		EnqueuedAt:  time.Now(),
+		Seq:         q.seq.Add(1),
+	}
+	if err := q.store.Save(ctx, cmd); err != nil {
+		return "", fmt.Errorf("failed to save queued command: %w", err)
+	}
+	return id, nil
+}
+
+// Drain sends every stored command, retrying each up to MaxAttempts times.
+// Delivered commands are deleted from the store; failed ones are saved with their attempt count
+// and last error, and remain queued for the next Drain. The returned error joins all failures.
+func (q *CommandQueue) Drain(ctx context.Context) error {
+	q.drainMu.Lock()
+	defer q.drainMu.Unlock()
+
+	cmds, err := q.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load queued commands: %w", err)
+	}
+	// Deliver in enqueue order regardless of the order the store returns. Commands enqueued
+	// within the same clock tick are ordered by Seq.
+	slices.SortFunc(cmds, func(a, b QueuedCommand) int {
+		if c := a.EnqueuedAt.Compare(b.EnqueuedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Seq, b.Seq)
+	})
+
+	var errs []error
+	for _, cmd := range cmds {
+		if err := q.deliver(ctx, cmd); err != nil {
+			errs = append(errs, err)
+		}
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver sends a single command with retries and updates the store accordingly.
+func (q *CommandQueue) deliver(ctx context.Context, cmd QueuedCommand) error {
+	var sendErr error
+	for attempt := 0; attempt < q.config.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			if err := q.client.sleep(ctx, q.config.RetryDelay); err != nil {
+				break
+			}
+		}
+		cmd.Attempts++
+		_, sendErr = q.client.SendDeviceCommand(ctx, cmd.DeviceID, cmd.Command, cmd.Parameter, cmd.CommandType)
+		if sendErr == nil {
+			if err := q.store.Delete(ctx, cmd.ID); err != nil {
+				return fmt.Errorf("command %s delivered but not removed from queue: %w", cmd.ID, err)
+			}
+			return nil
+		}
+	}
+
+	cmd.LastError = sendErr.Error()
+	if err := q.store.Save(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to save queued command %s after delivery error %v: %w", cmd.ID, sendErr, err)
+	}
+	return fmt.Errorf("failed to deliver queued command %s (%s to %s): %w", cmd.ID, cmd.Command, cmd.DeviceID, sendErr)
+}
+
+// Run drains the queue every interval (default one minute) until ctx is cancelled.
+// Delivery errors are passed to onError, if set. Panics in the store or in onError are recovered
+// (see WithPanicHandler); a panic while draining is passed to onError as a *PanicError.
+func (q *CommandQueue) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	for {
+		err := q.client.protect(func() error { return q.Drain(ctx) })
+		if err != nil && onError != nil {
+			// A panicking onError is recovered and reported to the panic handler only.
+			_ = q.client.protect(func() error {
+				onError(err)
+				return nil
+			})
+		}
+		if err := q.client.sleep(ctx, interval); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryQueueStore is an in-memory QueueStore for tests.
+type memoryQueueStore struct {
+	mu   sync.Mutex
+	cmds map[string]QueuedCommand
+}
+
+func newMemoryQueueStore() *memoryQueueStore {
+	return &memoryQueueStore{cmds: make(map[string]QueuedCommand)}
+}
+
+func (s *memoryQueueStore) Save(ctx context.Context, cmd QueuedCommand) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cmds[cmd.ID] = cmd
+	return nil
+}
+
+func (s *memoryQueueStore) Load(ctx context.Context) ([]QueuedCommand, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cmds := make([]QueuedCommand, 0, len(s.cmds))
+	for _, cmd := range s.cmds {
+		cmds = append(cmds, cmd)
+	}
+	return cmds, nil
+}
+
+func (s *memoryQueueStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cmds, id)
+	return nil
+}
+
+// newTestQueue creates a CommandQueue whose client does not sleep between retries.
+// options are applied after the defaults and may replace the sleeper.
+func newTestQueue(t *testing.T, handler http.HandlerFunc, store QueueStore, options ...ClientOption) *CommandQueue {
+	t.Helper()
+	_, server := setupMockServer(t, handler)
+	noSleep := func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	options = append([]ClientOption{WithBaseURL(server.URL), WithSleeper(noSleep)}, options...)
+	client, err := NewClient("mock-token", "mock-secret", options...)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	queue, err := NewCommandQueue(client, store, QueueConfig{MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("NewCommandQueue() returned error: %v", err)
+	}
+	return queue
+}
+
+func TestCommandQueue_DeliversAndRemoves(t *testing.T) {
+	var got []capturedCommand
+	store := newMemoryQueueStore()
+	queue := newTestQueue(t, commandCaptureHandler(t, &got), store)
+	ctx := context.Background()
+
+	if _, err := queue.Enqueue(ctx, "B1", "press", nil, ""); err != nil {
+		t.Fatalf("Enqueue() returned error: %v", err)
+	}
+	if _, err := queue.Enqueue(ctx, "L1", "setBrightness", 40, ""); err != nil {
+		t.Fatalf("Enqueue() returned error: %v", err)
+	}
+	if cmds, _ := store.Load(ctx); len(cmds) != 2 {
+		t.Fatalf("store has %d commands after Enqueue; want 2", len(cmds))
+	}
+
+	if err := queue.Drain(ctx); err != nil {
+		t.Fatalf("Drain() returned error: %v", err)
+	}
+	if len(got) != 2 || got[0].Command != "press" || got[1].Command != "setBrightness" {
+		t.Errorf("delivered %+v; want press then setBrightness", got)
+	}
+	if cmds, _ := store.Load(ctx); len(cmds) != 0 {
+		t.Errorf("store has %d commands after Drain; want 0", len(cmds))
+	}
+}
+
+func TestCommandQueue_OrdersSameTimestampBySeq(t *testing.T) {
+	var got []capturedCommand
+	store := newMemoryQueueStore()
+	queue := newTestQueue(t, commandCaptureHandler(t, &got), store)
+	ctx := context.Background()
+
+	commands := []string{"turnOn", "setBrightness", "setColor", "turnOff"}
+	for _, command := range commands {
+		if _, err := queue.Enqueue(ctx, "L1", command, nil, ""); err != nil {
+			t.Fatalf("Enqueue() returned error: %v", err)
+		}
+	}
+	// Simulate a coarse clock: every command shares one timestamp, so only Seq orders them.
+	enqueuedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for id, cmd := range store.cmds {
+		cmd.EnqueuedAt = enqueuedAt
+		store.cmds[id] = cmd
+	}
+
+	if err := queue.Drain(ctx); err != nil {
+		t.Fatalf("Drain() returned error: %v", err)
+	}
+	if len(got) != len(commands) {
+		t.Fatalf("delivered %d commands; want %d", len(got), len(commands))
+	}
+	for i, command := range commands {
+		if got[i].Command != command {
+			t.Errorf("delivery %d = %q; want %q", i, got[i].Command, command)
+		}
+	}
+}
+
+func TestCommandQueue_RetriesAndKeepsFailures(t *testing.T) {
+	var calls int32
+	failUntil := int32(2)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if atomic.AddInt32(&calls, 1) <= atomic.LoadInt32(&failUntil) {
+			fmt.Fprintln(w, `{"statusCode": 171, "message": "hub offline", "body": {}}`)
+			return
+		}
+		fmt.Fprintln(w, `{"statusCode": 100, "message": "success", "body": {}}`)
+	}
+	store := newMemoryQueueStore()
+	queue := newTestQueue(t, handler, store)
+	ctx := context.Background()
+
+	if _, err := queue.Enqueue(ctx, "B1", "press", nil, ""); err != nil {
+		t.Fatalf("Enqueue() returned error: %v", err)
+	}
+
+	// Two failures then success within the 3 allowed attempts
+	if err := queue.Drain(ctx); err != nil {
+		t.Fatalf("Drain() returned error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("sent %d times; want 3", calls)
+	}
+	if cmds, _ := store.Load(ctx); len(cmds) != 0 {
+		t.Errorf("store has %d commands; want 0", len(cmds))
+	}
+
+	// Persistent failure leaves the command queued with its attempts recorded
+	atomic.StoreInt32(&calls, 0)
+	atomic.StoreInt32(&failUntil, 100)
+	if _, err := queue.Enqueue(ctx, "B1", "press", nil, ""); err != nil {
+		t.Fatalf("Enqueue() returned error: %v", err)
+	}
+	if err := queue.Drain(ctx); err == nil {
+		t.Fatal("Drain() with failing API returned nil error")
+	}
+	cmds, _ := store.Load(ctx)
+	if len(cmds) != 1 {
+		t.Fatalf("store has %d commands; want 1", len(cmds))
+	}
+	if cmds[0].Attempts != 3 || cmds[0].LastError == "" {
+		t.Errorf("queued command = %+v; want 3 attempts and a last error", cmds[0])
+	}
+}
@@ -0,0 +1,135 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	var got []capturedCommand
+	capture := commandCaptureHandler(t, &got)
+	devices := statusHandler(`{"deviceList": [{"deviceId": "B1", "deviceName": "Bot", "deviceType": "Bot"}], "infraredRemoteList": []}`)
+	_, server := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			devices(w, r)
+			return
+		}
+		capture(w, r)
+	})
+
+	var recording bytes.Buffer
+	client, err := NewClient("secret-token", "secret-key", WithBaseURL(server.URL), WithRecorder(&recording))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := client.GetDevices(ctx); err != nil {
+		t.Fatalf("GetDevices() returned error: %v", err)
+	}
+	if _, err := client.SendDeviceCommand(ctx, "B1", "press", nil, ""); err != nil {
+		t.Fatalf("SendDeviceCommand() returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(recording.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("recorded %d lines; want 2:\n%s", len(lines), recording.String())
+	}
+	if strings.Contains(recording.String(), "secret-token") {
+		t.Error("recording contains the token")
+	}
+	var command Interaction
+	if err := json.Unmarshal([]byte(lines[1]), &command); err != nil {
+		t.Fatalf("failed to decode recorded line: %v", err)
+	}
+	if command.Method != http.MethodPost || !strings.HasSuffix(command.URL, "/v1.1/devices/B1/commands") || command.StatusCode != http.StatusOK {
+		t.Errorf("recorded %s %s (HTTP %d); want POST .../devices/B1/commands (HTTP 200)", command.Method, command.URL, command.StatusCode)
+	}
+	if !strings.Contains(command.RequestBody, `"press"`) || !strings.Contains(command.ResponseBody, `"statusCode": 100`) {
+		t.Errorf("recorded bodies = %q, %q; want the command and the API response", command.RequestBody, command.ResponseBody)
+	}
+	for _, key := range []string{"Authorization", "Sign"} {
+		if v := command.RequestHeader.Get(key); v != redactedValue {
+			t.Errorf("recorded %s header = %q; want %q", key, v, redactedValue)
+		}
+	}
+
+	// Replay offline: the default base URL is never contacted.
+	replayed, err := NewClient("other-token", "other-secret", WithHTTPClient(&http.Client{Transport: ReplayTransport(&recording)}))
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	resp, err := replayed.GetDevices(ctx)
+	if err != nil {
+		t.Fatalf("replayed GetDevices() returned error: %v", err)
+	}
+	if len(resp.DeviceList) != 1 || resp.DeviceList[0]["deviceId"] != "B1" {
+		t.Errorf("replayed devices = %v; want B1", resp.DeviceList)
+	}
+	if _, err := replayed.SendDeviceCommand(ctx, "B1", "press", nil, ""); err != nil {
+		t.Errorf("replayed SendDeviceCommand() returned error: %v", err)
+	}
+	if _, err := replayed.GetDevices(ctx); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("second replayed GetDevices() error = %v; want no recorded interaction", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("server received %d commands; want only the recorded one", len(got))
+	}
+
+	if _, err := ReplayTransport(strings.NewReader("not json")).RoundTrip(&http.Request{Method: http.MethodGet}); err == nil {
+		t.Error("ReplayTransport with a malformed recording did not return an error")
+	}
+}
+
+func TestWithRecorder_OptionOrder(t *testing.T) {
+	custom := &http.Client{Transport: &http.Transport{}}
+	orders := map[string]func(w io.Writer) []ClientOption{
+		"RecorderFirst": func(w io.Writer) []ClientOption {
+			return []ClientOption{WithRecorder(w), WithInsecureSkipTLSVerify(), WithHTTPClient(custom)}
+		},
+		"RecorderLast": func(w io.Writer) []ClientOption {
+			return []ClientOption{WithHTTPClient(custom), WithInsecureSkipTLSVerify(), WithRecorder(w)}
+		},
+	}
+	for name, options := range orders {
+		t.Run(name, func(t *testing.T) {
+			client, err := NewClient("token", "secret", options(io.Discard)...)
+			if err != nil {
+				t.Fatalf("NewClient() returned error: %v", err)
+			}
+			recorder, ok := client.httpClient.Transport.(*recordingTransport)
+			if !ok {
+				t.Fatalf("transport = %T; want the recorder outermost", client.httpClient.Transport)
+			}
+			base, ok := recorder.next.(*http.Transport)
+			if !ok || base == custom.Transport {
+				t.Fatalf("recorded transport = %T; want a clone of the custom *http.Transport", recorder.next)
+			}
+			if !base.TLSClientConfig.InsecureSkipVerify {
+				t.Error("recorded transport still verifies certificates")
+			}
+		})
+	}
+}
+
+func TestWithRecorder_MaxResponseBytes(t *testing.T) {
+	body := `{"deviceList": [], "padding": "` + strings.Repeat("x", 1024) + `"}`
+	var recording bytes.Buffer
+	client, _ := setupMockServer(t, statusHandler(body), WithMaxResponseBytes(64), WithRecorder(&recording))
+
+	if _, err := client.GetDevices(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("GetDevices() error = %v; want ErrResponseTooLarge", err)
+	}
+	var interaction Interaction
+	if err := json.Unmarshal(recording.Bytes(), &interaction); err != nil {
+		t.Fatalf("failed to decode recorded line: %v", err)
+	}
+	if len(interaction.ResponseBody) != 65 {
+		t.Errorf("recorded %d response bytes; want the limit plus one (65)", len(interaction.ResponseBody))
+	}
+}
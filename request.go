@@ -0,0 +1,78 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+)
+
+// RequestOption configures a single call to Do or Decode.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds per-request overrides. Nil fields fall back to the client's defaults.
+type requestOptions struct {
+	encoder JSONMarshal
+	decoder JSONUnmarshal
+}
+
+// WithRequestEncoder overrides the client's JSON encoder for one request.
+func WithRequestEncoder(encoder JSONMarshal) RequestOption {
+	return func(o *requestOptions) {
+		o.encoder = encoder
+	}
+}
+
+// WithRequestDecoder overrides the client's JSON decoder for one request, e.g. for an endpoint
+// whose body encodes numbers as strings.
+func WithRequestDecoder(decoder JSONUnmarshal) RequestOption {
+	return func(o *requestOptions) {
+		o.decoder = decoder
+	}
+}
+
+func newRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+type requestOptionsKey struct{}
+
+// codecFor returns the JSON encoder and decoder in effect for a request made with ctx.
+func (c *Client) codecFor(ctx context.Context) (JSONMarshal, JSONUnmarshal) {
+	encoder, decoder := c.jsonEncoder, c.jsonDecoder
+	if o, ok := ctx.Value(requestOptionsKey{}).(*requestOptions); ok {
+		if o.encoder != nil {
+			encoder = o.encoder
+		}
+		if o.decoder != nil {
+			decoder = o.decoder
+		}
+	}
+	return encoder, decoder
+}
+
+// Do sends a signed request to path (e.g. "/v1.1/devices") for endpoints without a dedicated method.
+// requestBody, if non-nil, is JSON-encoded. API errors are returned as *APIError, as for other methods.
+// With WithStrictStatusCodes(false), an undocumented non-100 status code is returned without error;
+// use Response.IsSuccess to tell it apart. Options apply to this call only.
+func (c *Client) Do(ctx context.Context, method, path string, requestBody interface{}, opts ...RequestOption) (*Response, error) {
+	if len(opts) > 0 {
+		ctx = context.WithValue(ctx, requestOptionsKey{}, newRequestOptions(opts))
+	}
+	return c.doRequest(ctx, method, path, requestBody)
+}
+
+// Decode unmarshals the body of a response returned by Do into v.
+// Options apply to this call only.
+func (c *Client) Decode(resp *Response, v interface{}, opts ...RequestOption) error {
+	if resp == nil {
+		return fmt.Errorf("response cannot be nil")
+	}
+	decoder := c.jsonDecoder
+	if o := newRequestOptions(opts); o.decoder != nil {
+		decoder = o.decoder
+	}
+	return describeDecodeError(resp.Body, v, decoder(resp.Body, v))
+}
@@ -0,0 +1,78 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDoAndDecode(t *testing.T) {
+	client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var req map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("Failed to decode request: %v", err)
+			}
+			if req["encodedBy"] != "override" {
+				t.Errorf("request body = %v; want override encoding", req)
+			}
+		}
+		statusHandler(`{"count": "42"}`)(w, r)
+	})
+
+	var overrideDecodes int
+	// numbersAsStrings strips the quotes around "42" so it decodes into an int.
+	numbersAsStrings := func(data []byte, v any) error {
+		overrideDecodes++
+		return json.Unmarshal([]byte(strings.ReplaceAll(string(data), `"42"`, `42`)), v)
+	}
+	overrideEncoder := func(v any) ([]byte, error) {
+		return json.Marshal(map[string]string{"encodedBy": "override"})
+	}
+
+	resp, err := client.Do(context.Background(), http.MethodPost, "/v1.1/custom", map[string]string{},
+		WithRequestEncoder(overrideEncoder), WithRequestDecoder(numbersAsStrings))
+	if err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	if overrideDecodes != 1 {
+		t.Errorf("override decoder used %d times for Do; want 1", overrideDecodes)
+	}
+
+	var body struct {
+		Count int `json:"count"`
+	}
+	if err := client.Decode(resp, &body, WithRequestDecoder(numbersAsStrings)); err != nil {
+		t.Fatalf("Decode() returned error: %v", err)
+	}
+	if body.Count != 42 {
+		t.Errorf("Count = %d; want 42", body.Count)
+	}
+
+	// Without options the client's default codec applies again.
+	overrideDecodes = 0
+	resp, err = client.Do(context.Background(), http.MethodGet, "/v1.1/custom", nil)
+	if err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	if err := client.Decode(resp, &body); err == nil {
+		t.Error("Decode() with default decoder accepted a string for an int field")
+	}
+	if overrideDecodes != 0 {
+		t.Errorf("override decoder used %d times after the overriding call; want 0", overrideDecodes)
+	}
+}
+
+func TestDo_APIError(t *testing.T) {
+	client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 152, "message": "device not found", "body": {}}`)
+	})
+	if _, err := client.Do(context.Background(), http.MethodGet, "/v1.1/devices/X/status", nil); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("Do() error = %v; want ErrDeviceNotFound", err)
+	}
+}
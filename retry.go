@@ -0,0 +1,69 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultRateLimitBackoff is the first delay before retrying a 429 response without a
+// Retry-After header. It doubles with each further retry.
+const defaultRateLimitBackoff = time.Second
+
+// WithRateLimitRetry retries requests rejected with HTTP 429 up to maxRetries times.
+// Each retry waits for the duration in the response's Retry-After header (seconds or HTTP-date),
+// or, without one, for an exponential backoff starting at one second. Retried requests are signed
+// again with a fresh nonce. A 429 is never processed by the API, so retrying commands is safe.
+func WithRateLimitRetry(maxRetries int) ClientOption {
+	return func(c *Client) error {
+		if maxRetries < 0 {
+			return fmt.Errorf("maxRetries cannot be negative, got %d", maxRetries)
+		}
+		c.rateLimitRetries = maxRetries
+		return nil
+	}
+}
+
+// doRequestTimed is doRequest that also reports the HTTP round-trip duration of the last attempt,
+// measured from sending the request until the response body has been read.
+// The duration is zero if the request could not be sent. 429 responses are retried per WithRateLimitRetry.
+func (c *Client) doRequestTimed(ctx context.Context, method, path string, requestBody interface{}) (*Response, time.Duration, error) {
+	for attempt := 0; ; attempt++ {
+		resp, took, err := c.doAttempt(ctx, method, path, requestBody)
+		var apiErr *APIError
+		if attempt >= c.rateLimitRetries || !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusTooManyRequests {
+			return resp, took, err
+		}
+		delay := apiErr.RetryAfter
+		if delay <= 0 {
+			delay = defaultRateLimitBackoff << attempt
+		}
+		c.loggerFor(ctx).DebugContext(ctx, "rate limited, retrying", "method", method, "path", path, "delay", delay, "attempt", attempt+1)
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, took, err
+		}
+	}
+}
+
+// parseRetryAfter parses a Retry-After header value, given either as delay-seconds or as an
+// HTTP-date. It returns 0 if the value is missing, invalid, or a date in the past.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,146 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// rateLimitHandler replies 429 with the given Retry-After header for the first `limited` requests.
+func rateLimitHandler(retryAfter func() string, limited int, calls *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		if *calls <= limited {
+			if v := retryAfter(); v != "" {
+				w.Header().Set("Retry-After", v)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintln(w, `{"message": "Too Many Requests"}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"statusCode": 100, "message": "success", "body": {"deviceList": []}}`)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"120", 2 * time.Minute},
+		{" 3 ", 3 * time.Second},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"-5", 0},
+		{"soon", 0},
+		{"", 0},
+	}
+	for _, tc := range testCases {
+		if got := parseRetryAfter(tc.value, now); got != tc.want {
+			t.Errorf("parseRetryAfter(%q) = %v; want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	newClient := func(t *testing.T, handler http.HandlerFunc, slept *[]time.Duration, opts ...ClientOption) *Client {
+		t.Helper()
+		_, server := setupMockServer(t, handler)
+		sleeper := func(ctx context.Context, d time.Duration) error {
+			*slept = append(*slept, d)
+			return ctx.Err()
+		}
+		client, err := NewClient("token", "secret", append([]ClientOption{WithBaseURL(server.URL), WithSleeper(sleeper)}, opts...)...)
+		if err != nil {
+			t.Fatalf("NewClient() returned error: %v", err)
+		}
+		return client
+	}
+
+	t.Run("WithoutRetrySeconds", func(t *testing.T) {
+		var calls int
+		var slept []time.Duration
+		client := newClient(t, rateLimitHandler(func() string { return "7" }, 1, &calls), &slept)
+
+		_, err := client.GetDevices(context.Background())
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, ErrTooManyRequests) {
+			t.Fatalf("GetDevices() error = %v; want ErrTooManyRequests", err)
+		}
+		if apiErr.RetryAfter != 7*time.Second {
+			t.Errorf("RetryAfter = %v; want 7s", apiErr.RetryAfter)
+		}
+		if calls != 1 || len(slept) != 0 {
+			t.Errorf("made %d requests and slept %v; want no retry", calls, slept)
+		}
+	})
+
+	t.Run("WithoutRetryHTTPDate", func(t *testing.T) {
+		var calls int
+		var slept []time.Duration
+		retryAt := func() string { return time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat) }
+		client := newClient(t, rateLimitHandler(retryAt, 1, &calls), &slept)
+
+		_, err := client.GetDevices(context.Background())
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("GetDevices() error = %v; want *APIError", err)
+		}
+		// HTTP-dates have one-second resolution.
+		if apiErr.RetryAfter < 28*time.Second || apiErr.RetryAfter > 30*time.Second {
+			t.Errorf("RetryAfter = %v; want about 30s", apiErr.RetryAfter)
+		}
+	})
+
+	t.Run("RetryHonorsSeconds", func(t *testing.T) {
+		var calls int
+		var slept []time.Duration
+		client := newClient(t, rateLimitHandler(func() string { return "4" }, 2, &calls), &slept, WithRateLimitRetry(3))
+
+		if _, err := client.GetDevices(context.Background()); err != nil {
+			t.Fatalf("GetDevices() returned error: %v", err)
+		}
+		if calls != 3 || len(slept) != 2 || slept[0] != 4*time.Second || slept[1] != 4*time.Second {
+			t.Errorf("made %d requests and slept %v; want 3 requests with 4s waits", calls, slept)
+		}
+	})
+
+	t.Run("RetryHonorsHTTPDate", func(t *testing.T) {
+		var calls int
+		var slept []time.Duration
+		retryAt := func() string { return time.Now().Add(20 * time.Second).UTC().Format(http.TimeFormat) }
+		client := newClient(t, rateLimitHandler(retryAt, 1, &calls), &slept, WithRateLimitRetry(1))
+
+		if _, err := client.GetDevices(context.Background()); err != nil {
+			t.Fatalf("GetDevices() returned error: %v", err)
+		}
+		if len(slept) != 1 || slept[0] < 18*time.Second || slept[0] > 20*time.Second {
+			t.Errorf("slept %v; want about 20s", slept)
+		}
+	})
+
+	t.Run("RetryDefaultBackoff", func(t *testing.T) {
+		var calls int
+		var slept []time.Duration
+		client := newClient(t, rateLimitHandler(func() string { return "" }, 5, &calls), &slept, WithRateLimitRetry(2))
+
+		_, err := client.GetDevices(context.Background())
+		if !errors.Is(err, ErrTooManyRequests) {
+			t.Errorf("GetDevices() error = %v; want ErrTooManyRequests after retries run out", err)
+		}
+		if calls != 3 || len(slept) != 2 || slept[0] != time.Second || slept[1] != 2*time.Second {
+			t.Errorf("made %d requests and slept %v; want 3 requests with 1s, 2s backoff", calls, slept)
+		}
+	})
+
+	t.Run("NegativeRetries", func(t *testing.T) {
+		if _, err := NewClient("token", "secret", WithRateLimitRetry(-1)); err == nil {
+			t.Error("WithRateLimitRetry(-1) did not return an error")
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package switchbot
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -16,21 +17,47 @@ type Scene struct {
 
 // GetScenes retrieves the list of manual scenes configured by the user.
 func (c *Client) GetScenes(ctx context.Context) ([]Scene, error) {
-	path := fmt.Sprintf("/%s/scenes", apiVersion)
+	path := fmt.Sprintf("/%s/scenes", c.apiVersion)
 	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var scenes []Scene
-	if err := json.Unmarshal(resp.Body, &scenes); err != nil {
-		// Handle case where body might be empty array "[]" which is valid JSON
-		if string(resp.Body) == "[]" {
-			return []Scene{}, nil // Return empty slice
+	scenes, err := decodeScenes(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("GetScenes response: %w", err)
+	}
+
+	return scenes, nil
+}
+
+// sceneListKeys are the object keys under which a scene list may be nested.
+var sceneListKeys = []string{"scenes", "sceneList"}
+
+// decodeScenes parses a scene list that is either a bare array or nested under one of sceneListKeys.
+func decodeScenes(body json.RawMessage) ([]Scene, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || string(trimmed) == "null" || string(trimmed) == "[]" {
+		return []Scene{}, nil // Return empty slice
+	}
+
+	if trimmed[0] == '{' {
+		var nested map[string]json.RawMessage
+		if err := decodeBody(trimmed, &nested); err != nil {
+			return nil, err
+		}
+		for _, key := range sceneListKeys {
+			if list, ok := nested[key]; ok {
+				return decodeScenes(list)
+			}
 		}
-		return nil, fmt.Errorf("failed to unmarshal GetScenes response body: %w, body: %s", err, string(resp.Body))
+		return nil, fmt.Errorf("no scene list found under keys %q, body: %s", sceneListKeys, bodySnippet(trimmed))
 	}
 
+	var scenes []Scene
+	if err := decodeBody(trimmed, &scenes); err != nil {
+		return nil, err
+	}
 	return scenes, nil
 }
 
@@ -40,7 +67,22 @@ func (c *Client) ExecuteScene(ctx context.Context, sceneID string) error {
 	if sceneID == "" {
 		return fmt.Errorf("sceneID cannot be empty")
 	}
-	path := fmt.Sprintf("/%s/scenes/%s/execute", apiVersion, sceneID)
+	path := fmt.Sprintf("/%s/scenes/%s/execute", c.apiVersion, sceneID)
 	_, err := c.doRequest(ctx, http.MethodPost, path, nil)
 	return err
 }
+
+// ExecuteSceneWithResponse is ExecuteScene that returns the response body instead of discarding it.
+// The body is usually empty, but may carry a commandId (see CommandResponse.CommandID).
+// An empty body yields an empty CommandResponse regardless of EmptyBodyPolicy.
+func (c *Client) ExecuteSceneWithResponse(ctx context.Context, sceneID string) (CommandResponse, error) {
+	if sceneID == "" {
+		return nil, fmt.Errorf("sceneID cannot be empty")
+	}
+	path := fmt.Sprintf("/%s/scenes/%s/execute", c.apiVersion, sceneID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.decodeCommandResponse(resp.Body, sceneID)
+}
@@ -0,0 +1,87 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestGetScenes(t *testing.T) {
+	testCases := []struct {
+		name      string
+		body      string
+		wantCount int
+		wantErr   bool
+	}{
+		{"BareArray", `[{"sceneId": "S1", "sceneName": "Good Night"}, {"sceneId": "S2", "sceneName": "Away"}]`, 2, false},
+		{"EmptyArray", `[]`, 0, false},
+		{"NestedObject", `{"scenes": [{"sceneId": "S1", "sceneName": "Good Night"}]}`, 1, false},
+		{"NestedSceneList", `{"sceneList": []}`, 0, false},
+		{"EmptyObject", `{}`, 0, true},
+		{"Malformed", `"not a list"`, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := setupMockServer(t, statusHandler(tc.body))
+
+			scenes, err := client.GetScenes(context.Background())
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("GetScenes() returned %v; want error", scenes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetScenes() returned error: %v", err)
+			}
+			if scenes == nil {
+				t.Error("GetScenes() returned nil slice; want non-nil")
+			}
+			if len(scenes) != tc.wantCount {
+				t.Errorf("GetScenes() returned %d scenes; want %d", len(scenes), tc.wantCount)
+			}
+			if tc.wantCount > 0 && scenes[0].SceneID != "S1" {
+				t.Errorf("first scene ID = %q; want S1", scenes[0].SceneID)
+			}
+		})
+	}
+}
+
+func TestExecuteSceneWithResponse(t *testing.T) {
+	t.Run("CommandID", func(t *testing.T) {
+		client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/v1.1/scenes/S1/execute" {
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			statusHandler(`{"commandId": "CMD-1"}`)(w, r)
+		})
+
+		resp, err := client.ExecuteSceneWithResponse(context.Background(), "S1")
+		if err != nil {
+			t.Fatalf("ExecuteSceneWithResponse() returned error: %v", err)
+		}
+		if resp.CommandID() != "CMD-1" {
+			t.Errorf("CommandID() = %q; want CMD-1", resp.CommandID())
+		}
+	})
+
+	t.Run("EmptyBody", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(`{}`))
+
+		resp, err := client.ExecuteSceneWithResponse(context.Background(), "S1")
+		if err != nil {
+			t.Fatalf("ExecuteSceneWithResponse() returned error: %v", err)
+		}
+		if resp == nil || len(resp) != 0 {
+			t.Errorf("ExecuteSceneWithResponse() = %v; want empty map", resp)
+		}
+	})
+
+	t.Run("EmptySceneID", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(`{}`))
+		if _, err := client.ExecuteSceneWithResponse(context.Background(), ""); err == nil {
+			t.Error("ExecuteSceneWithResponse() with empty sceneID returned nil error")
+		}
+	})
+}
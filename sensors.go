@@ -0,0 +1,97 @@
+package switchbot
+
+import "context"
+
+// Brightness is the ambient light level reported by motion and contact sensors.
+type Brightness string
+
+const (
+	BrightnessBright Brightness = "bright"
+	BrightnessDim    Brightness = "dim"
+)
+
+// OpenState is the door/window state reported by a Contact Sensor.
+type OpenState string
+
+const (
+	OpenStateOpen            OpenState = "open"
+	OpenStateClose           OpenState = "close"
+	OpenStateTimeOutNotClose OpenState = "timeOutNotClose" // Left open longer than the configured timeout
+)
+
+// MotionSensorStatus represents the status of a Motion Sensor.
+type MotionSensorStatus struct {
+	reportedStatus // Provides ReportedAt
+
+	DeviceID     string     `json:"deviceId"`
+	DeviceType   string     `json:"deviceType"`
+	HubDeviceID  string     `json:"hubDeviceId"`
+	Version      string     `json:"version"`
+	MoveDetected bool       `json:"moveDetected"`
+	Brightness   Brightness `json:"brightness"`
+	Battery      FlexInt    `json:"battery"` // Percentage (0-100)
+	_            struct{}
+}
+
+// ContactSensorStatus represents the status of a Contact Sensor.
+type ContactSensorStatus struct {
+	reportedStatus // Provides ReportedAt
+
+	DeviceID     string     `json:"deviceId"`
+	DeviceType   string     `json:"deviceType"`
+	HubDeviceID  string     `json:"hubDeviceId"`
+	Version      string     `json:"version"`
+	MoveDetected bool       `json:"moveDetected"`
+	OpenState    OpenState  `json:"openState"`
+	Brightness   Brightness `json:"brightness"`
+	Battery      FlexInt    `json:"battery"` // Percentage (0-100)
+	_            struct{}
+}
+
+// WaterLeakStatus represents the status of a Water Leak Detector.
+type WaterLeakStatus struct {
+	reportedStatus // Provides ReportedAt
+
+	DeviceID    string  `json:"deviceId"`
+	DeviceType  string  `json:"deviceType"`
+	HubDeviceID string  `json:"hubDeviceId"`
+	Version     string  `json:"version"`
+	Status      FlexInt `json:"status"`  // 0 = dry, 1 = leak detected; see IsLeaking
+	Battery     FlexInt `json:"battery"` // Percentage (0-100)
+	_           struct{}
+}
+
+// IsLeaking reports whether the detector reports a leak (status 1). Status 0 means dry.
+func (s *WaterLeakStatus) IsLeaking() bool {
+	return s.Status == 1
+}
+
+// GetMotionSensorStatus retrieves the typed status of a Motion Sensor.
+// Returns ErrDeviceTypeMismatch if the device is not a Motion Sensor.
+func (c *Client) GetMotionSensorStatus(ctx context.Context, deviceID string) (*MotionSensorStatus, error) {
+	var status MotionSensorStatus
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, DeviceTypeMotionSensor); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetContactSensorStatus retrieves the typed status of a Contact Sensor.
+// Returns ErrDeviceTypeMismatch if the device is not a Contact Sensor.
+func (c *Client) GetContactSensorStatus(ctx context.Context, deviceID string) (*ContactSensorStatus, error) {
+	var status ContactSensorStatus
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, DeviceTypeContactSensor); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetWaterLeakStatus retrieves the typed status of a Water Leak Detector.
+// Returns ErrDeviceTypeMismatch if the device is not a Water Leak Detector.
+func (c *Client) GetWaterLeakStatus(ctx context.Context, deviceID string) (*WaterLeakStatus, error) {
+	var status WaterLeakStatus
+	if err := c.getTypedDeviceStatus(ctx, deviceID, &status, DeviceTypeWaterLeakDetector); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
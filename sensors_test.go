@@ -0,0 +1,88 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetMotionSensorStatus(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(`{"deviceId": "M1", "deviceType": "Motion Sensor", "hubDeviceId": "H1", "battery": 87, "version": "V1.2", "moveDetected": true, "brightness": "dim"}`))
+
+	status, err := client.GetMotionSensorStatus(context.Background(), "M1")
+	if err != nil {
+		t.Fatalf("GetMotionSensorStatus() returned error: %v", err)
+	}
+	if !status.MoveDetected {
+		t.Error("MoveDetected = false; want true")
+	}
+	if status.Brightness != BrightnessDim {
+		t.Errorf("Brightness = %q; want %q", status.Brightness, BrightnessDim)
+	}
+	if status.Battery != 87 {
+		t.Errorf("Battery = %d; want 87", status.Battery)
+	}
+}
+
+func TestGetContactSensorStatus(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(`{"deviceId": "C1", "deviceType": "Contact Sensor", "hubDeviceId": "H1", "battery": 60, "version": "V1.1", "moveDetected": false, "openState": "timeOutNotClose", "brightness": "bright"}`))
+
+	status, err := client.GetContactSensorStatus(context.Background(), "C1")
+	if err != nil {
+		t.Fatalf("GetContactSensorStatus() returned error: %v", err)
+	}
+	if status.OpenState != OpenStateTimeOutNotClose {
+		t.Errorf("OpenState = %q; want %q", status.OpenState, OpenStateTimeOutNotClose)
+	}
+	if status.Brightness != BrightnessBright {
+		t.Errorf("Brightness = %q; want %q", status.Brightness, BrightnessBright)
+	}
+	if status.Battery != 60 {
+		t.Errorf("Battery = %d; want 60", status.Battery)
+	}
+}
+
+func TestGetWaterLeakStatus(t *testing.T) {
+	testCases := []struct {
+		name    string
+		status  string
+		leaking bool
+	}{
+		{"Dry", `0`, false},
+		{"Leak", `1`, true},
+		{"LeakAsString", `"1"`, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := setupMockServer(t, statusHandler(`{"deviceId": "W1", "deviceType": "Water Detector", "hubDeviceId": "H1", "battery": "90", "version": "V1.0", "status": `+tc.status+`}`))
+
+			status, err := client.GetWaterLeakStatus(context.Background(), "W1")
+			if err != nil {
+				t.Fatalf("GetWaterLeakStatus() returned error: %v", err)
+			}
+			if status.IsLeaking() != tc.leaking {
+				t.Errorf("IsLeaking() = %v; want %v (status %d)", status.IsLeaking(), tc.leaking, status.Status)
+			}
+			if status.Battery != 90 || status.Version != "V1.0" {
+				t.Errorf("Battery = %d, Version = %q; want 90, V1.0", status.Battery, status.Version)
+			}
+		})
+	}
+}
+
+func TestGetSensorStatus_DeviceTypeMismatch(t *testing.T) {
+	client, _ := setupMockServer(t, statusHandler(`{"deviceId": "B1", "deviceType": "Bot", "power": "on"}`))
+
+	_, err := client.GetMotionSensorStatus(context.Background(), "B1")
+	if !errors.Is(err, ErrDeviceTypeMismatch) {
+		t.Errorf("GetMotionSensorStatus() error = %v; want ErrDeviceTypeMismatch", err)
+	}
+	_, err = client.GetContactSensorStatus(context.Background(), "B1")
+	if !errors.Is(err, ErrDeviceTypeMismatch) {
+		t.Errorf("GetContactSensorStatus() error = %v; want ErrDeviceTypeMismatch", err)
+	}
+	_, err = client.GetWaterLeakStatus(context.Background(), "B1")
+	if !errors.Is(err, ErrDeviceTypeMismatch) {
+		t.Errorf("GetWaterLeakStatus() error = %v; want ErrDeviceTypeMismatch", err)
+	}
+}
@@ -0,0 +1,61 @@
+package switchbot
+
+// PowerState is the power state reported by switchable devices (Bot, Plug, lights, etc.).
+type PowerState string
+
+const (
+	PowerStateOn  PowerState = "on"
+	PowerStateOff PowerState = "off"
+)
+
+// String implements fmt.Stringer.
+func (s PowerState) String() string {
+	if s == "" {
+		return "unknown"
+	}
+	return string(s)
+}
+
+// LockState is the state reported by a Smart Lock or Smart Lock Pro.
+type LockState string
+
+const (
+	LockStateLocked   LockState = "locked"
+	LockStateUnlocked LockState = "unlocked"
+	LockStateJammed   LockState = "jammed"
+	LockStateDeadbolt LockState = "deadbolt" // Smart Lock Pro only: locked with the deadbolt, latch free
+	LockStateLatch    LockState = "latch"    // Smart Lock Pro only: held by the latch, deadbolt retracted
+)
+
+// String implements fmt.Stringer.
+func (s LockState) String() string {
+	if s == "" {
+		return "unknown"
+	}
+	return string(s)
+}
+
+// CurtainMode is the motor mode used by the Curtain setPosition command.
+type CurtainMode string
+
+const (
+	CurtainModePerformance CurtainMode = "0"
+	CurtainModeSilent      CurtainMode = "1"
+	CurtainModeDefault     CurtainMode = "ff"
+)
+
+// String implements fmt.Stringer.
+func (b Brightness) String() string {
+	if b == "" {
+		return "unknown"
+	}
+	return string(b)
+}
+
+// String implements fmt.Stringer.
+func (s OpenState) String() string {
+	if s == "" {
+		return "unknown"
+	}
+	return string(s)
+}
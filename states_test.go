@@ -0,0 +1,53 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestStateStringers(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value fmt.Stringer
+		want  string
+	}{
+		{"PowerStateOn", PowerStateOn, "on"},
+		{"PowerStateEmpty", PowerState(""), "unknown"},
+		{"LockStateJammed", LockStateJammed, "jammed"},
+		{"LockStateDeadbolt", LockStateDeadbolt, "deadbolt"},
+		{"LockStateUnrecognized", LockState("calibrating"), "calibrating"},
+		{"BrightnessDim", BrightnessDim, "dim"},
+		{"OpenStateClose", OpenStateClose, "close"},
+		{"OpenStateTimeOut", OpenStateTimeOutNotClose, "timeOutNotClose"},
+		{"BotModePress", BotModePress, "pressMode"},
+		{"BotModeEmpty", BotMode(""), "unknown"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := fmt.Sprintf("%s", tc.value); got != tc.want {
+				t.Errorf("%%s formatting = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStateStringers_JSONUnchanged(t *testing.T) {
+	status := ContactSensorStatus{OpenState: OpenStateClose, Brightness: BrightnessBright}
+	b, err := json.Marshal(status)
+	if err != nil {
+		t.Fatalf("Failed to marshal status: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal status: %v", err)
+	}
+	if decoded["openState"] != "close" {
+		t.Errorf("openState = %v; want %q", decoded["openState"], "close")
+	}
+	if decoded["brightness"] != "bright" {
+		t.Errorf("brightness = %v; want %q", decoded["brightness"], "bright")
+	}
+}
@@ -0,0 +1,171 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDeviceTypeMismatch is returned by typed status getters when the device reports a different deviceType.
+var ErrDeviceTypeMismatch = errors.New("device type mismatch")
+
+// reportedTimeKeys are the status fields that carry when a reading was taken, in order of preference.
+var reportedTimeKeys = []string{"timeOfSample", "lastUpdateTime"}
+
+// reportedTime returns when the status was reported, or the zero time if the status has no timestamp.
+// Timestamps may be in seconds or milliseconds.
+func reportedTime(status DeviceStatus) time.Time {
+	for _, key := range reportedTimeKeys {
+		if ts, ok := statusFloat(status[key]); ok && ts > 0 {
+			return epochToTime(int64(ts))
+		}
+	}
+	return time.Time{}
+}
+
+// reportedStatus is embedded in typed status structs to carry the reading's timestamp.
+type reportedStatus struct {
+	ReportedAt time.Time `json:"-"` // When the reading was taken; zero if the device does not report it
+}
+
+func (r *reportedStatus) setReportedAt(t time.Time) {
+	r.ReportedAt = t
+}
+
+// Battery returns the battery percentage from the status' "battery" field (matched
+// case-insensitively), accepting both numbers and numeric strings. It returns false when the
+// device reports no battery, e.g. a mains-powered Plug, or the value is not numeric.
+func (s DeviceStatus) Battery() (int, bool) {
+	if v, ok := s["battery"]; ok {
+		return statusInt(v)
+	}
+	for key, v := range s {
+		if strings.EqualFold(key, "battery") {
+			return statusInt(v)
+		}
+	}
+	return 0, false
+}
+
+// Int returns the integer value of key, accepting json.Number (see WithJSONNumbers), float64
+// and numeric strings. json.Number and string values are parsed exactly, so large integers do
+// not lose precision. It returns false if key is missing or its value is not a whole number.
+func (s DeviceStatus) Int(key string) (int64, bool) {
+	switch v := s[key].(type) {
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// Float returns the value of key as a float64, accepting json.Number (see WithJSONNumbers),
+// float64 and numeric strings. It returns false if key is missing or its value is not numeric.
+func (s DeviceStatus) Float(key string) (float64, bool) {
+	return statusFloat(s[key])
+}
+
+// DiffStatus returns the keys whose values differ between old and new, each mapped to its
+// [old, new] pair, e.g. {"openState": {"open", "close"}}. A key present in only one status is
+// reported with nil on the missing side. Values are compared shallowly with reflect.DeepEqual:
+// a nested map or slice is reported whole if anything inside it changed, and values of different
+// types never match, so 85 (float64) and "85" are a change. The result is empty, not nil, if
+// nothing changed.
+func DiffStatus(old, new DeviceStatus) map[string][2]interface{} {
+	diff := make(map[string][2]interface{})
+	for key, oldValue := range old {
+		newValue, ok := new[key]
+		if !ok || !reflect.DeepEqual(oldValue, newValue) {
+			diff[key] = [2]interface{}{oldValue, newValue}
+		}
+	}
+	for key, newValue := range new {
+		if _, ok := old[key]; !ok {
+			diff[key] = [2]interface{}{nil, newValue}
+		}
+	}
+	return diff
+}
+
+// GetLastReportedTime returns when the device last reported its status, or the zero time
+// if the status carries no timestamp. Use it to detect stale sensors.
+func (c *Client) GetLastReportedTime(ctx context.Context, deviceID string) (time.Time, error) {
+	status, err := c.GetDeviceStatus(ctx, deviceID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return reportedTime(status), nil
+}
+
+// IsDeviceOnline reports whether a device answers status requests: true if GetDeviceStatus
+// succeeds and false, with a nil error, if the API reports the device offline (ErrDeviceOffline,
+// status code 161). Any other failure, including ErrHubOffline, is returned as an error.
+func (c *Client) IsDeviceOnline(ctx context.Context, deviceID string) (bool, error) {
+	if _, err := c.GetDeviceStatus(ctx, deviceID); err != nil {
+		if errors.Is(err, ErrDeviceOffline) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// getDeviceStatusBody fetches the raw status body of a physical device.
+func (c *Client) getDeviceStatusBody(ctx context.Context, deviceID string) (json.RawMessage, error) {
+	if deviceID == "" {
+		return nil, fmt.Errorf("deviceID cannot be empty")
+	}
+	path := fmt.Sprintf("/%s/devices/%s/status", c.apiVersion, deviceID)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// getTypedDeviceStatus fetches a device status and unmarshals it into v,
+// failing with ErrDeviceTypeMismatch if the reported deviceType is not one of deviceTypes.
+// An empty body always fails with ErrEmptyBody, whatever the EmptyBodyPolicy, since it
+// carries no deviceType to check.
+func (c *Client) getTypedDeviceStatus(ctx context.Context, deviceID string, v any, deviceTypes ...string) error {
+	body, err := c.getDeviceStatusBody(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if isEmptyJSONBody(body) {
+		return fmt.Errorf("%w: device status for %s", ErrEmptyBody, deviceID)
+	}
+
+	var status DeviceStatus
+	if err := decodeBody(body, &status); err != nil {
+		return fmt.Errorf("device status for %s: %w", deviceID, err)
+	}
+	deviceType, _ := status["deviceType"].(string)
+	if !slices.Contains(deviceTypes, deviceType) {
+		return fmt.Errorf("%w: device %s is %q, want one of %q", ErrDeviceTypeMismatch, deviceID, deviceType, deviceTypes)
+	}
+
+	if err := decodeBody(body, v); err != nil {
+		return fmt.Errorf("%s status for %s: %w", deviceType, deviceID, err)
+	}
+	if r, ok := v.(interface{ setReportedAt(time.Time) }); ok {
+		r.setReportedAt(reportedTime(status))
+	}
+	return nil
+}
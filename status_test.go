@@ -0,0 +1,208 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReportedAt(t *testing.T) {
+	t.Run("TypedStatus", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(`{"deviceId": "S1", "deviceType": "Motion Sensor", "moveDetected": true, "battery": 80, "timeOfSample": 1700000000123}`))
+
+		status, err := client.GetMotionSensorStatus(context.Background(), "S1")
+		if err != nil {
+			t.Fatalf("GetMotionSensorStatus() returned error: %v", err)
+		}
+		if want := time.UnixMilli(1700000000123); !status.ReportedAt.Equal(want) {
+			t.Errorf("ReportedAt = %v; want %v", status.ReportedAt, want)
+		}
+	})
+
+	t.Run("TypedStatusWithoutTimestamp", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(`{"deviceId": "M1", "deviceType": "Meter", "temperature": 20}`))
+
+		status, err := client.GetMeterStatus(context.Background(), "M1")
+		if err != nil {
+			t.Fatalf("GetMeterStatus() returned error: %v", err)
+		}
+		if !status.ReportedAt.IsZero() {
+			t.Errorf("ReportedAt = %v; want zero time", status.ReportedAt)
+		}
+	})
+
+	t.Run("GetLastReportedTime", func(t *testing.T) {
+		client, _ := setupMockServer(t, statusHandler(`{"deviceId": "X1", "deviceType": "Hub 2", "lastUpdateTime": 1700000000}`))
+
+		reported, err := client.GetLastReportedTime(context.Background(), "X1")
+		if err != nil {
+			t.Fatalf("GetLastReportedTime() returned error: %v", err)
+		}
+		if want := time.Unix(1700000000, 0); !reported.Equal(want) {
+			t.Errorf("GetLastReportedTime() = %v; want %v", reported, want)
+		}
+	})
+}
+
+func TestDeviceStatus_Battery(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status DeviceStatus
+		want   int
+		wantOK bool
+	}{
+		{"Number", DeviceStatus{"battery": float64(85)}, 85, true},
+		{"String", DeviceStatus{"battery": " 42 "}, 42, true},
+		{"Float", DeviceStatus{"battery": 99.0}, 99, true},
+		{"CaseInsensitive", DeviceStatus{"Battery": float64(10)}, 10, true},
+		{"Zero", DeviceStatus{"battery": float64(0)}, 0, true},
+		{"MainsPowered", DeviceStatus{"deviceType": "Plug", "power": "on"}, 0, false},
+		{"NotNumeric", DeviceStatus{"battery": "low"}, 0, false},
+		{"Nil", nil, 0, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.status.Battery()
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("Battery() = %d, %v; want %d, %v", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestWithJSONNumbers(t *testing.T) {
+	body := `{"deviceId": "P1", "deviceType": "Plug Mini (JP)", "voltage": 100.123456789012345, "electricCurrent": 9007199254740993, "battery": 80}`
+
+	client, server := setupMockServer(t, statusHandler(body))
+	status, err := client.GetDeviceStatus(context.Background(), "P1")
+	if err != nil {
+		t.Fatalf("GetDeviceStatus() returned error: %v", err)
+	}
+	if _, ok := status["electricCurrent"].(float64); !ok {
+		t.Errorf("default electricCurrent is %T; want float64", status["electricCurrent"])
+	}
+
+	client, err = NewClient("token", "secret", WithBaseURL(server.URL), WithJSONNumbers())
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	status, err = client.GetDeviceStatus(context.Background(), "P1")
+	if err != nil {
+		t.Fatalf("GetDeviceStatus() returned error: %v", err)
+	}
+	if n, ok := status["voltage"].(json.Number); !ok || n.String() != "100.123456789012345" {
+		t.Errorf("voltage = %#v; want json.Number 100.123456789012345", status["voltage"])
+	}
+	if n, ok := status.Int("electricCurrent"); !ok || n != 9007199254740993 {
+		t.Errorf("Int(electricCurrent) = %d, %v; want 9007199254740993 exactly", n, ok)
+	}
+	if battery, ok := status.Battery(); !ok || battery != 80 {
+		t.Errorf("Battery() = %d, %v; want 80", battery, ok)
+	}
+}
+
+func TestDeviceStatus_IntFloat(t *testing.T) {
+	status := DeviceStatus{
+		"number":   json.Number("42"),
+		"fraction": json.Number("1.5"),
+		"float":    float64(7),
+		"string":   " 12 ",
+		"text":     "on",
+	}
+	intCases := []struct {
+		key    string
+		want   int64
+		wantOK bool
+	}{
+		{"number", 42, true},
+		{"fraction", 0, false},
+		{"float", 7, true},
+		{"string", 12, true},
+		{"text", 0, false},
+		{"missing", 0, false},
+	}
+	for _, tc := range intCases {
+		if got, ok := status.Int(tc.key); got != tc.want || ok != tc.wantOK {
+			t.Errorf("Int(%q) = %d, %v; want %d, %v", tc.key, got, ok, tc.want, tc.wantOK)
+		}
+	}
+	if got, ok := status.Float("fraction"); !ok || got != 1.5 {
+		t.Errorf("Float(fraction) = %v, %v; want 1.5", got, ok)
+	}
+	if _, ok := status.Float("text"); ok {
+		t.Error("Float(text) ok = true; want false")
+	}
+}
+
+func TestDiffStatus(t *testing.T) {
+	old := DeviceStatus{
+		"openState":  "open",
+		"battery":    float64(80),
+		"brightness": "bright",
+		"extra":      map[string]interface{}{"a": float64(1)},
+		"removed":    true,
+	}
+	new := DeviceStatus{
+		"openState":  "close",
+		"battery":    float64(80),
+		"brightness": "bright",
+		"extra":      map[string]interface{}{"a": float64(2)},
+		"added":      nil,
+	}
+
+	got := DiffStatus(old, new)
+	want := map[string][2]interface{}{
+		"openState": {"open", "close"},
+		"extra":     {map[string]interface{}{"a": float64(1)}, map[string]interface{}{"a": float64(2)}},
+		"removed":   {true, nil},
+		"added":     {nil, nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffStatus() = %v; want %v", got, want)
+	}
+
+	if diff := DiffStatus(old, old); diff == nil || len(diff) != 0 {
+		t.Errorf("DiffStatus(old, old) = %#v; want empty map", diff)
+	}
+	if diff := DiffStatus(nil, DeviceStatus{"power": "on"}); len(diff) != 1 || diff["power"] != [2]interface{}{nil, "on"} {
+		t.Errorf("DiffStatus(nil, ...) = %v; want power added", diff)
+	}
+	if diff := DiffStatus(DeviceStatus{"battery": float64(85)}, DeviceStatus{"battery": "85"}); len(diff) != 1 {
+		t.Errorf("DiffStatus() = %v; want a type change reported", diff)
+	}
+}
+
+func TestIsDeviceOnline(t *testing.T) {
+	apiStatus := func(code int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"statusCode": %d, "message": "", "body": {"deviceId": "D1"}}`, code)
+		}
+	}
+	tests := []struct {
+		name    string
+		code    int
+		want    bool
+		wantErr error
+	}{
+		{"Online", 100, true, nil},
+		{"DeviceOffline", 161, false, nil},
+		{"HubOffline", 171, false, ErrHubOffline},
+		{"NotFound", 152, false, ErrDeviceNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := setupMockServer(t, apiStatus(tt.code))
+
+			online, err := client.IsDeviceOnline(context.Background(), "D1")
+			if online != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("IsDeviceOnline() = %v, %v; want %v, %v", online, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
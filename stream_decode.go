@@ -0,0 +1,73 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// JSONStreamDecoder decodes a JSON value read from r into v,
+// e.g. func(r io.Reader, v any) error { return json.NewDecoder(r).Decode(v) }.
+type JSONStreamDecoder func(r io.Reader, v any) error
+
+// streamCaptureBytes is how much of a streamed response body is kept for error reporting.
+const streamCaptureBytes = 4 << 10
+
+// WithStreamingDecoder decodes the response envelope with a stream decoder reading the HTTP body,
+// for JSON libraries that work on an io.Reader. The envelope's body is still held as raw JSON and
+// decoded into the target type afterwards, so this does not reduce peak memory.
+// The first 4KB of the body are captured for APIError and decode error messages.
+// A per-request WithRequestDecoder takes precedence and uses the byte-based path.
+func WithStreamingDecoder(decoder JSONStreamDecoder) ClientOption {
+	return func(c *Client) error {
+		if decoder == nil {
+			return fmt.Errorf("JSONStreamDecoder cannot be nil")
+		}
+		c.streamDecoder = decoder
+		return nil
+	}
+}
+
+// streamDecoderFor returns the streaming decoder in effect for ctx, or nil if the
+// byte-based decoder should be used.
+func (c *Client) streamDecoderFor(ctx context.Context) JSONStreamDecoder {
+	if o, ok := ctx.Value(requestOptionsKey{}).(*requestOptions); ok && o.decoder != nil {
+		return nil
+	}
+	return c.streamDecoder
+}
+
+// prefixWriter keeps the first limit bytes written to it and discards the rest.
+type prefixWriter struct {
+	buf   []byte
+	limit int
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	if room := w.limit - len(w.buf); room > 0 {
+		w.buf = append(w.buf, p[:min(room, len(p))]...)
+	}
+	return len(p), nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// streamDecode decodes body into v with decoder, reading at most maxBytes+1 bytes.
+// It returns a prefix of the body for error reporting, whether the body exceeded maxBytes,
+// and the decoder's error.
+func streamDecode(body io.Reader, decoder JSONStreamDecoder, maxBytes int64, v any) (captured []byte, tooLarge bool, err error) {
+	capture := &prefixWriter{limit: streamCaptureBytes}
+	counter := &countingReader{r: io.LimitReader(body, maxBytes+1)}
+	err = decoder(io.TeeReader(counter, capture), v)
+	return capture.buf, counter.n > maxBytes, err
+}
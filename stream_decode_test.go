@@ -0,0 +1,108 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newStreamingClient(t *testing.T, handler http.HandlerFunc, opts ...ClientOption) (*Client, *int) {
+	t.Helper()
+	_, server := setupMockServer(t, handler)
+	var calls int
+	stream := func(r io.Reader, v any) error {
+		calls++
+		return json.NewDecoder(r).Decode(v)
+	}
+	client, err := NewClient("token", "secret", append([]ClientOption{WithBaseURL(server.URL), WithStreamingDecoder(stream)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	return client, &calls
+}
+
+func TestWithStreamingDecoder(t *testing.T) {
+	t.Run("NilRejected", func(t *testing.T) {
+		if _, err := NewClient("token", "secret", WithStreamingDecoder(nil)); err == nil {
+			t.Error("WithStreamingDecoder(nil) did not return an error")
+		}
+	})
+
+	t.Run("DecodesLargeDeviceList", func(t *testing.T) {
+		remotes := make([]string, 500)
+		for i := range remotes {
+			remotes[i] = fmt.Sprintf(`{"deviceId": "IR%d", "deviceName": "Remote %d", "remoteType": "TV"}`, i, i)
+		}
+		body := fmt.Sprintf(`{"deviceList": [], "infraredRemoteList": [%s]}`, strings.Join(remotes, ","))
+		client, calls := newStreamingClient(t, statusHandler(body))
+
+		devices, err := client.GetDevices(context.Background())
+		if err != nil {
+			t.Fatalf("GetDevices() returned error: %v", err)
+		}
+		if len(devices.InfraredRemoteList) != 500 || devices.InfraredRemoteList[499].DeviceID != "IR499" {
+			t.Errorf("decoded %d remotes; want 500", len(devices.InfraredRemoteList))
+		}
+		if *calls != 1 {
+			t.Errorf("streaming decoder called %d times; want 1", *calls)
+		}
+	})
+
+	t.Run("UnparsableErrorBodyCaptured", func(t *testing.T) {
+		client, _ := newStreamingClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "<html>bad gateway</html>")
+		})
+
+		_, err := client.GetDevices(context.Background())
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("GetDevices() error = %v; want *APIError", err)
+		}
+		if apiErr.HTTPStatus != http.StatusBadGateway || string(apiErr.Body) != "<html>bad gateway</html>" {
+			t.Errorf("APIError = {HTTPStatus: %d, Body: %q}; want the captured body", apiErr.HTTPStatus, apiErr.Body)
+		}
+	})
+
+	t.Run("APIStatusError", func(t *testing.T) {
+		client, _ := newStreamingClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"statusCode": 152, "message": "device not found", "body": {}}`)
+		})
+
+		_, err := client.GetDeviceStatus(context.Background(), "D1")
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != 152 {
+			t.Errorf("GetDeviceStatus() error = %v; want APIError with statusCode 152", err)
+		}
+	})
+
+	t.Run("ResponseTooLarge", func(t *testing.T) {
+		client, _ := newStreamingClient(t, statusHandler(`{"deviceList": [], "padding": "`+strings.Repeat("x", 256)+`"}`), WithMaxResponseBytes(128))
+
+		if _, err := client.GetDevices(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("GetDevices() error = %v; want ErrResponseTooLarge", err)
+		}
+	})
+
+	t.Run("RequestDecoderTakesPrecedence", func(t *testing.T) {
+		client, calls := newStreamingClient(t, statusHandler(`{"deviceId": "D1"}`))
+
+		var used bool
+		decoder := func(data []byte, v any) error {
+			used = true
+			return json.Unmarshal(data, v)
+		}
+		if _, err := client.Do(context.Background(), http.MethodGet, "/v1.1/devices/D1/status", nil, WithRequestDecoder(decoder)); err != nil {
+			t.Fatalf("Do() returned error: %v", err)
+		}
+		if !used || *calls != 0 {
+			t.Errorf("request decoder used = %v, streaming decoder calls = %d; want only the request decoder", used, *calls)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// swingHandler serves a device list for GET requests and captures commands.
+func swingHandler(t *testing.T, got *[]capturedCommand) http.HandlerFunc {
+	capture := commandCaptureHandler(t, got)
+	devices := statusHandler(`{
+		"deviceList": [
+			{"deviceId": "F1", "deviceType": "Circulator Fan"},
+			{"deviceId": "B1", "deviceType": "Bot"}
+		],
+		"infraredRemoteList": [
+			{"deviceId": "IR1", "remoteType": "Fan"},
+			{"deviceId": "IR2", "remoteType": "Air Conditioner"}
+		]
+	}`)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			devices(w, r)
+			return
+		}
+		capture(w, r)
+	}
+}
+
+func TestSetSwing(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, swingHandler(t, &got))
+	ctx := context.Background()
+
+	// IR fans only toggle, and the circulator fan, Bot and AC remote have no documented swing command
+	for _, id := range []string{"IR1", "F1", "B1", "IR2"} {
+		if err := client.SetSwing(ctx, id, false); !errors.Is(err, ErrUnknownCommand) {
+			t.Errorf("SetSwing(%s) error = %v; want ErrUnknownCommand", id, err)
+		}
+	}
+	err := client.SetSwing(ctx, "X1", true)
+	if !errors.Is(err, ErrDeviceNotInList) {
+		t.Errorf("SetSwing(X1) error = %v; want ErrDeviceNotInList", err)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Errorf("SetSwing(X1) error = %v; want no APIError for a local lookup", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("commands = %+v; want none sent", got)
+	}
+}
+
+func TestToggleSwing(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, swingHandler(t, &got))
+	ctx := context.Background()
+
+	if err := client.ToggleSwing(ctx, "IR1"); err != nil {
+		t.Fatalf("ToggleSwing(IR1) returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].Command != "swing" || got[0].Parameter != "default" {
+		t.Errorf("commands = %+v; want swing(default)", got)
+	}
+
+	got = nil
+	for _, id := range []string{"F1", "B1"} {
+		if err := client.ToggleSwing(ctx, id); !errors.Is(err, ErrUnknownCommand) {
+			t.Errorf("ToggleSwing(%s) error = %v; want ErrUnknownCommand", id, err)
+		}
+	}
+	if len(got) != 0 {
+		t.Errorf("commands = %+v; want none sent", got)
+	}
+}
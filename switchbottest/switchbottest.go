@@ -0,0 +1,258 @@
+// Package switchbottest provides an in-memory fake of the SwitchBot API for examples and for
+// integration tests of applications built on switchbot-go.
+//
+// A Server serves canned device lists, statuses and scenes over httptest, accepts commands and
+// scene executions, and records them for assertions:
+//
+//	server := switchbottest.NewServer(switchbottest.State{
+//		Devices:  []switchbot.Device{{"deviceId": "B1", "deviceName": "Bot", "deviceType": "Bot"}},
+//		Statuses: map[string]switchbot.DeviceStatus{"B1": {"deviceId": "B1", "deviceType": "Bot", "power": "off"}},
+//	})
+//	defer server.Close()
+//	client, _ := server.Client()
+//	client.SendDeviceCommand(ctx, "B1", "turnOn", nil, "")
+//	server.Commands() // [{DeviceID: B1, Command: turnOn, ...}]
+//
+// No real credentials are needed; requests are accepted as long as they are signed.
+package switchbottest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	switchbot "github.com/mktbsh/switchbot-go"
+)
+
+// State is the account served by a Server.
+type State struct {
+	Devices         []switchbot.Device                // Physical devices returned by GetDevices
+	InfraredRemotes []switchbot.InfraredRemoteDevice  // Virtual IR remotes returned by GetDevices
+	Statuses        map[string]switchbot.DeviceStatus // Status by deviceId; devices without one report only their ID
+	Scenes          []switchbot.Scene                 // Manual scenes returned by GetScenes
+	_               struct{}
+}
+
+// Command is a device command received by a Server.
+type Command struct {
+	DeviceID    string
+	Command     string
+	CommandType string
+	Parameter   interface{} // Decoded from JSON, so numbers are float64
+	_           struct{}
+}
+
+// Server is an httptest-backed fake of the SwitchBot API. It is safe for concurrent use.
+type Server struct {
+	URL string // Base URL of the fake, for switchbot.WithBaseURL
+
+	server *httptest.Server
+
+	mu             sync.Mutex
+	state          State
+	commands       []Command
+	executedScenes []string
+}
+
+// NewServer starts a fake serving state. Call Close when done.
+// Commands named turnOn and turnOff update the "power" field of the device's status.
+func NewServer(state State) *Server {
+	s := &Server{state: cloneState(state)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{version}/devices", s.handleDevices)
+	mux.HandleFunc("GET /{version}/devices/{id}/status", s.handleStatus)
+	mux.HandleFunc("POST /{version}/devices/{id}/commands", s.handleCommand)
+	mux.HandleFunc("GET /{version}/scenes", s.handleScenes)
+	mux.HandleFunc("POST /{version}/scenes/{id}/execute", s.handleExecuteScene)
+	s.server = httptest.NewServer(requireSignature(mux))
+	s.URL = s.server.URL
+	return s
+}
+
+// NewClient starts a fake serving state and returns a Client connected to it.
+// The fake is closed when the test ends.
+func NewClient(t testing.TB, state State, opts ...switchbot.ClientOption) (*switchbot.Client, *Server) {
+	t.Helper()
+	s := NewServer(state)
+	t.Cleanup(s.Close)
+	client, err := s.Client(opts...)
+	if err != nil {
+		t.Fatalf("switchbottest: failed to create client: %v", err)
+	}
+	return client, s
+}
+
+// Client returns a Client connected to the fake with placeholder credentials.
+// opts are applied after the base URL is set.
+func (s *Server) Client(opts ...switchbot.ClientOption) (*switchbot.Client, error) {
+	return switchbot.NewClient("fake-token", "fake-secret", append([]switchbot.ClientOption{switchbot.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// Close shuts the fake down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Commands returns the device commands received so far, in order.
+func (s *Server) Commands() []Command {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.commands)
+}
+
+// ExecutedScenes returns the IDs of the scenes executed so far, in order.
+func (s *Server) ExecutedScenes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.executedScenes)
+}
+
+// SetStatus replaces the status served for deviceID.
+func (s *Server) SetStatus(deviceID string, status switchbot.DeviceStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.Statuses[deviceID] = maps.Clone(status)
+}
+
+// Status returns the status currently served for deviceID, including changes made by commands.
+func (s *Server) Status(deviceID string) (switchbot.DeviceStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.state.Statuses[deviceID]
+	return maps.Clone(status), ok
+}
+
+func (s *Server) handleDevices(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeResponse(w, 100, "success", switchbot.GetDevicesResponse{
+		DeviceList:         orEmpty(s.state.Devices),
+		InfraredRemoteList: orEmpty(s.state.InfraredRemotes),
+	})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.hasDevice(id) {
+		writeResponse(w, 152, "device not found", struct{}{})
+		return
+	}
+	status, ok := s.state.Statuses[id]
+	if !ok {
+		status = switchbot.DeviceStatus{"deviceId": id}
+	}
+	writeResponse(w, 100, "success", status)
+}
+
+func (s *Server) handleCommand(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req switchbot.CommandRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, 190, fmt.Sprintf("invalid command body: %v", err), struct{}{})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.hasDevice(id) {
+		writeResponse(w, 152, "device not found", struct{}{})
+		return
+	}
+	s.commands = append(s.commands, Command{DeviceID: id, Command: req.Command, CommandType: req.CommandType, Parameter: req.Parameter})
+	if status, ok := s.state.Statuses[id]; ok && req.CommandType == "command" {
+		switch req.Command {
+		case "turnOn":
+			status["power"] = "on"
+		case "turnOff":
+			status["power"] = "off"
+		}
+	}
+	writeResponse(w, 100, "success", struct{}{})
+}
+
+func (s *Server) handleScenes(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeResponse(w, 100, "success", orEmpty(s.state.Scenes))
+}
+
+func (s *Server) handleExecuteScene(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.ContainsFunc(s.state.Scenes, func(scene switchbot.Scene) bool { return scene.SceneID == id }) {
+		writeResponse(w, 190, "scene not found", struct{}{})
+		return
+	}
+	s.executedScenes = append(s.executedScenes, id)
+	writeResponse(w, 100, "success", struct{}{})
+}
+
+// hasDevice reports whether id is a physical device or IR remote. s.mu must be held.
+func (s *Server) hasDevice(id string) bool {
+	for _, d := range s.state.Devices {
+		if d["deviceId"] == id {
+			return true
+		}
+	}
+	for _, ir := range s.state.InfraredRemotes {
+		if ir.DeviceID == id {
+			return true
+		}
+	}
+	_, ok := s.state.Statuses[id]
+	return ok
+}
+
+// requireSignature rejects requests without the signing headers, as the real API does.
+func requireSignature(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, header := range []string{"Authorization", "T", "Sign", "Nonce"} {
+			if r.Header.Get(header) == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprintf(w, `{"message": "missing %s header"}`, header)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeResponse writes the standard SwitchBot response envelope.
+func writeResponse(w http.ResponseWriter, statusCode int, message string, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"statusCode": statusCode,
+		"message":    message,
+		"body":       body,
+	})
+}
+
+// cloneState copies state so the caller's maps are not modified by commands.
+func cloneState(state State) State {
+	statuses := make(map[string]switchbot.DeviceStatus, len(state.Statuses))
+	for id, status := range state.Statuses {
+		statuses[id] = maps.Clone(status)
+	}
+	state.Statuses = statuses
+	state.Devices = slices.Clone(state.Devices)
+	state.InfraredRemotes = slices.Clone(state.InfraredRemotes)
+	state.Scenes = slices.Clone(state.Scenes)
+	return state
+}
+
+// orEmpty returns s, or an empty slice if s is nil, so it encodes as [] rather than null.
+func orEmpty[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
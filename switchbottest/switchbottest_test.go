@@ -0,0 +1,110 @@
+package switchbottest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	switchbot "github.com/mktbsh/switchbot-go"
+)
+
+func testState() State {
+	return State{
+		Devices: []switchbot.Device{
+			{"deviceId": "B1", "deviceName": "Kettle Bot", "deviceType": "Bot"},
+		},
+		InfraredRemotes: []switchbot.InfraredRemoteDevice{
+			{DeviceID: "IR1", DeviceName: "TV", RemoteType: string(switchbot.RemoteTypeTV)},
+		},
+		Statuses: map[string]switchbot.DeviceStatus{
+			"B1": {"deviceId": "B1", "deviceType": "Bot", "power": "off", "battery": 90},
+		},
+		Scenes: []switchbot.Scene{{SceneID: "S1", SceneName: "Good night"}},
+	}
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	state := testState()
+	client, server := NewClient(t, state)
+
+	devices, err := client.GetDevices(ctx)
+	if err != nil {
+		t.Fatalf("GetDevices() returned error: %v", err)
+	}
+	if len(devices.DeviceList) != 1 || len(devices.InfraredRemoteList) != 1 || devices.InfraredRemoteList[0].Type() != switchbot.RemoteTypeTV {
+		t.Errorf("GetDevices() = %+v", devices)
+	}
+
+	if _, err := client.SendDeviceCommand(ctx, "B1", "turnOn", nil, ""); err != nil {
+		t.Fatalf("SendDeviceCommand() returned error: %v", err)
+	}
+	if _, err := client.SendDeviceCommand(ctx, "IR1", "SetChannel", 4, ""); err != nil {
+		t.Fatalf("SendDeviceCommand() returned error: %v", err)
+	}
+	commands := server.Commands()
+	if len(commands) != 2 || commands[0].Command != "turnOn" || commands[1].DeviceID != "IR1" || commands[1].Parameter != float64(4) {
+		t.Errorf("Commands() = %+v", commands)
+	}
+
+	bot, err := client.GetBotStatus(ctx, "B1")
+	if err != nil {
+		t.Fatalf("GetBotStatus() returned error: %v", err)
+	}
+	if bot.Power != switchbot.PowerStateOn || bot.Battery != 90 {
+		t.Errorf("GetBotStatus() = %+v; want power on after turnOn", *bot)
+	}
+	if state.Statuses["B1"]["power"] != "off" {
+		t.Error("the caller's State was modified by a command")
+	}
+
+	if err := client.ExecuteScene(ctx, "S1"); err != nil {
+		t.Fatalf("ExecuteScene() returned error: %v", err)
+	}
+	if scenes := server.ExecutedScenes(); len(scenes) != 1 || scenes[0] != "S1" {
+		t.Errorf("ExecutedScenes() = %v; want [S1]", scenes)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	ctx := context.Background()
+	client, server := NewClient(t, testState())
+
+	if _, err := client.GetDeviceStatus(ctx, "missing"); !errors.Is(err, switchbot.ErrDeviceNotFound) {
+		t.Errorf("GetDeviceStatus(missing) error = %v; want ErrDeviceNotFound", err)
+	}
+	if _, err := client.SendDeviceCommand(ctx, "missing", "turnOn", nil, ""); !errors.Is(err, switchbot.ErrDeviceNotFound) {
+		t.Errorf("SendDeviceCommand(missing) error = %v; want ErrDeviceNotFound", err)
+	}
+	if len(server.Commands()) != 0 {
+		t.Errorf("Commands() = %v; commands to unknown devices should not be recorded", server.Commands())
+	}
+
+	resp, err := http.Get(server.URL + "/v1.1/devices")
+	if err != nil {
+		t.Fatalf("http.Get() returned error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unsigned request got HTTP %d; want 401", resp.StatusCode)
+	}
+}
+
+func TestServer_SetStatus(t *testing.T) {
+	client, server := NewClient(t, State{})
+
+	server.SetStatus("M1", switchbot.DeviceStatus{"deviceId": "M1", "deviceType": "Meter", "temperature": 21.5})
+	status, err := client.GetDeviceStatus(context.Background(), "M1")
+	if err != nil {
+		t.Fatalf("GetDeviceStatus() returned error: %v", err)
+	}
+	if status["temperature"] != 21.5 {
+		t.Errorf("GetDeviceStatus() = %v", status)
+	}
+
+	scenes, err := client.GetScenes(context.Background())
+	if err != nil || len(scenes) != 0 {
+		t.Errorf("GetScenes() = %v, %v; want an empty list", scenes, err)
+	}
+}
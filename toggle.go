@@ -0,0 +1,52 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoPowerState is returned by ToggleDevice when the device status has no usable power field,
+// e.g. for sensors.
+var ErrNoPowerState = errors.New("device has no power state")
+
+// ToggleDevice turns a device off if its status reports power on, and on otherwise, returning
+// the response to the command sent. The power field may be "on"/"off" (in any case) or a boolean.
+// Returns ErrNoPowerState, without sending a command, if the status has no power field or it
+// holds any other value.
+//
+// The status is read first (one extra API call), so two concurrent toggles may both send the
+// same command.
+func (c *Client) ToggleDevice(ctx context.Context, deviceID string) (CommandResponse, error) {
+	status, err := c.GetDeviceStatus(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	on, ok := powerOn(status["power"])
+	if !ok {
+		deviceType, _ := status["deviceType"].(string)
+		return nil, fmt.Errorf("%w: device %s (%q) reports power %v", ErrNoPowerState, deviceID, deviceType, status["power"])
+	}
+	command := "turnOn"
+	if on {
+		command = "turnOff"
+	}
+	return c.SendDeviceCommand(ctx, deviceID, command, nil, "")
+}
+
+// powerOn interprets a power status value, reporting false if it is neither on/off nor a boolean.
+func powerOn(v interface{}) (on, ok bool) {
+	switch p := v.(type) {
+	case bool:
+		return p, true
+	case string:
+		switch strings.ToLower(p) {
+		case string(PowerStateOn):
+			return true, true
+		case string(PowerStateOff):
+			return false, true
+		}
+	}
+	return false, false
+}
@@ -0,0 +1,62 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestToggleDevice(t *testing.T) {
+	tests := []struct {
+		name  string
+		power string
+		want  string
+	}{
+		{"StringOn", `"on"`, "turnOff"},
+		{"StringOff", `"off"`, "turnOn"},
+		{"UpperCase", `"ON"`, "turnOff"},
+		{"BoolTrue", `true`, "turnOff"},
+		{"BoolFalse", `false`, "turnOn"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []capturedCommand
+			capture := commandCaptureHandler(t, &got)
+			client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					statusHandler(fmt.Sprintf(`{"deviceId": "P1", "deviceType": "Plug Mini (US)", "power": %s}`, tt.power))(w, r)
+					return
+				}
+				capture(w, r)
+			})
+
+			if _, err := client.ToggleDevice(context.Background(), "P1"); err != nil {
+				t.Fatalf("ToggleDevice() returned error: %v", err)
+			}
+			if len(got) != 1 || got[0].Command != tt.want {
+				t.Errorf("commands = %+v; want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("NoPowerField", func(t *testing.T) {
+		var got []capturedCommand
+		capture := commandCaptureHandler(t, &got)
+		client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				statusHandler(`{"deviceId": "M1", "deviceType": "Meter", "temperature": 21.5}`)(w, r)
+				return
+			}
+			capture(w, r)
+		})
+
+		if _, err := client.ToggleDevice(context.Background(), "M1"); !errors.Is(err, ErrNoPowerState) {
+			t.Errorf("ToggleDevice() error = %v; want ErrNoPowerState", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("commands = %+v; want none sent", got)
+		}
+	})
+}
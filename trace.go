@@ -0,0 +1,31 @@
+package switchbot
+
+import (
+	"context"
+	"log/slog"
+)
+
+// TraceIDHeader is the request header that carries the trace ID set with WithTraceID.
+const TraceIDHeader = "X-Trace-Id"
+
+type traceIDKey struct{}
+
+// WithTraceID returns a copy of ctx carrying a trace or correlation ID. Requests made with the
+// returned context send it in the TraceIDHeader header, and client log output includes it as "traceId".
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceIDFromContext returns the trace ID set with WithTraceID, or "" if there is none.
+func TraceIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// loggerFor returns the client logger, annotated with the trace ID from ctx if there is one.
+func (c *Client) loggerFor(ctx context.Context) *slog.Logger {
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		return c.logger.With("traceId", traceID)
+	}
+	return c.logger
+}
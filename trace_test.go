@@ -0,0 +1,42 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithTraceID(t *testing.T) {
+	var gotHeaders []string
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, _ := setupMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = append(gotHeaders, r.Header.Get(TraceIDHeader))
+		statusHandler(`{}`)(w, r)
+	}, WithLogger(logger))
+
+	ctx := WithTraceID(context.Background(), "trace-123")
+	if _, err := client.SendDeviceCommand(ctx, "B1", "press", nil, ""); err != nil {
+		t.Fatalf("SendDeviceCommand() returned error: %v", err)
+	}
+	if gotHeaders[0] != "trace-123" {
+		t.Errorf("%s header = %q; want trace-123", TraceIDHeader, gotHeaders[0])
+	}
+	if !strings.Contains(logs.String(), "traceId=trace-123") {
+		t.Errorf("log output = %q; want traceId", logs.String())
+	}
+
+	logs.Reset()
+	if _, err := client.SendDeviceCommand(context.Background(), "B1", "press", nil, ""); err != nil {
+		t.Fatalf("SendDeviceCommand() returned error: %v", err)
+	}
+	if gotHeaders[1] != "" {
+		t.Errorf("%s header = %q without a trace ID; want none", TraceIDHeader, gotHeaders[1])
+	}
+	if strings.Contains(logs.String(), "traceId") {
+		t.Errorf("log output = %q; want no traceId", logs.String())
+	}
+}
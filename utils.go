@@ -1,16 +1,31 @@
 package switchbot
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 )
 
 const uuidV7Format = "%x-%x-%x-%x-%x"
 
+// uuidV7CounterBits is the width of the RFC 9562 (Section 6.2, Method 1) monotonic counter:
+// the 12 rand_a bits followed by the top 6 bits of rand_b.
+const uuidV7CounterBits = 18
+
+// uuidV7State guards the timestamp and counter of the last generated UUIDv7.
+var uuidV7State struct {
+	mu      sync.Mutex
+	lastMs  int64
+	counter uint32
+}
+
 // getUUIDv7 generates a UUIDv7 (time-based) value.
+// Values generated within the same millisecond are ordered by a counter that is seeded randomly
+// each millisecond, so successive values strictly increase.
 func getUUIDv7() ([16]byte, error) {
 	var value [16]byte
 	_, err := rand.Read(value[:])
@@ -18,13 +33,37 @@ func getUUIDv7() ([16]byte, error) {
 		return value, err
 	}
 
-	ts := big.NewInt(time.Now().UnixMilli())
+	ms, counter := nextUUIDv7Counter(time.Now().UnixMilli(), value)
+
+	ts := big.NewInt(ms)
 	ts.FillBytes(value[0:6])
-	value[6] = (value[6] & 0x0F) | 0x70
-	value[8] = (value[8] & 0x3F) | 0x80
+	value[6] = 0x70 | byte(counter>>14)&0x0F
+	value[7] = byte(counter >> 6)
+	value[8] = 0x80 | byte(counter)&0x3F
 	return value, nil
 }
 
+// nextUUIDv7Counter returns the timestamp and counter for the next UUIDv7.
+// The counter is seeded from random with its top bit clear, leaving room to increment; if it
+// still overflows, the timestamp is advanced by one millisecond as permitted by RFC 9562.
+func nextUUIDv7Counter(nowMs int64, random [16]byte) (int64, uint32) {
+	seed := (uint32(random[6])<<16 | uint32(random[7])<<8 | uint32(random[8])) & (1<<(uuidV7CounterBits-1) - 1)
+
+	uuidV7State.mu.Lock()
+	defer uuidV7State.mu.Unlock()
+	switch {
+	case nowMs > uuidV7State.lastMs:
+		uuidV7State.lastMs = nowMs
+		uuidV7State.counter = seed
+	case uuidV7State.counter+1 < 1<<uuidV7CounterBits:
+		uuidV7State.counter++
+	default:
+		uuidV7State.lastMs++
+		uuidV7State.counter = seed
+	}
+	return uuidV7State.lastMs, uuidV7State.counter
+}
+
 // getUUIDv7String generates a UUIDv7 (time-based) value and returns it as a string.
 func getUUIDv7String() (string, error) {
 	value, err := getUUIDv7()
@@ -35,7 +74,9 @@ func getUUIDv7String() (string, error) {
 	return fmt.Sprintf(uuidV7Format, value[:4], value[4:6], value[6:8], value[8:10], value[10:]), nil
 }
 
-// isEmptyJSONBody checks if the JSON body is empty or contains only null or empty object.
+// isEmptyJSONBody checks if the JSON body is empty or contains only null or empty object,
+// ignoring surrounding whitespace.
 func isEmptyJSONBody(body json.RawMessage) bool {
+	body = bytes.TrimSpace(body)
 	return len(body) == 0 || string(body) == "{}" || string(body) == "null"
 }
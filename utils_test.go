@@ -1,6 +1,7 @@
 package switchbot
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/json"
 	"regexp"
@@ -97,6 +98,47 @@ func TestGetUUIDv7(t *testing.T) {
 	})
 }
 
+func TestGetUUIDv7_Monotonic(t *testing.T) {
+	t.Run("TightLoop", func(t *testing.T) {
+		const iterations = 100000
+		prev, err := getUUIDv7()
+		if err != nil {
+			t.Fatalf("getUUIDv7() failed: %v", err)
+		}
+		for i := 1; i < iterations; i++ {
+			value, err := getUUIDv7()
+			if err != nil {
+				t.Fatalf("getUUIDv7() failed on iteration %d: %v", i, err)
+			}
+			if bytes.Compare(value[:], prev[:]) <= 0 {
+				t.Fatalf("getUUIDv7() not strictly increasing on iteration %d: %x after %x", i, value, prev)
+			}
+			prev = value
+		}
+	})
+
+	t.Run("CounterOverflowAdvancesTimestamp", func(t *testing.T) {
+		future := time.Now().Add(time.Hour).UnixMilli()
+		uuidV7State.mu.Lock()
+		uuidV7State.lastMs = future
+		uuidV7State.counter = 1<<uuidV7CounterBits - 1
+		uuidV7State.mu.Unlock()
+
+		ms, counter := nextUUIDv7Counter(future, [16]byte{})
+		if ms != future+1 {
+			t.Errorf("timestamp = %d; want %d", ms, future+1)
+		}
+		if counter >= 1<<(uuidV7CounterBits-1) {
+			t.Errorf("counter = %d; want a reseeded value with the top bit clear", counter)
+		}
+
+		// Reset so later tests see timestamps close to the wall clock.
+		uuidV7State.mu.Lock()
+		uuidV7State.lastMs = 0
+		uuidV7State.mu.Unlock()
+	})
+}
+
 func TestGetUUIDv7String(t *testing.T) {
 	t.Run("NoError", func(t *testing.T) {
 		_, err := getUUIDv7String()
@@ -202,7 +244,17 @@ func TestIsEmptyJSONBody(t *testing.T) {
 		{
 			name:     "Whitespace only",
 			input:    json.RawMessage("   "),
-			expected: false,
+			expected: true, // Empty once surrounding whitespace is trimmed
+		},
+		{
+			name:     "Whitespace-padded Empty Object",
+			input:    json.RawMessage(" {}\n"),
+			expected: true,
+		},
+		{
+			name:     "Whitespace-padded Null",
+			input:    json.RawMessage("\tnull \r\n"),
+			expected: true,
 		},
 		{
 			name:     "Number",
@@ -0,0 +1,62 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestVacuumCommands(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+	ctx := context.Background()
+
+	if err := client.StartVacuum(ctx, "V1"); err != nil {
+		t.Fatalf("StartVacuum() returned error: %v", err)
+	}
+	if err := client.StopVacuum(ctx, "V1"); err != nil {
+		t.Fatalf("StopVacuum() returned error: %v", err)
+	}
+	if err := client.DockVacuum(ctx, "V1"); err != nil {
+		t.Fatalf("DockVacuum() returned error: %v", err)
+	}
+	if err := client.SetVacuumPowerLevel(ctx, "V1", VacuumPowerStrong); err != nil {
+		t.Fatalf("SetVacuumPowerLevel() returned error: %v", err)
+	}
+
+	want := []struct {
+		command   string
+		parameter interface{}
+	}{
+		{"start", "default"},
+		{"stop", "default"},
+		{"dock", "default"},
+		{"PowLevel", float64(2)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("received %d commands; want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Path != "/v1.1/devices/V1/commands" {
+			t.Errorf("command %d path = %q; want /v1.1/devices/V1/commands", i, got[i].Path)
+		}
+		if got[i].Command != w.command || got[i].Parameter != w.parameter {
+			t.Errorf("command %d = %s(%v); want %s(%v)", i, got[i].Command, got[i].Parameter, w.command, w.parameter)
+		}
+	}
+}
+
+func TestSetVacuumPowerLevel_OutOfRange(t *testing.T) {
+	var got []capturedCommand
+	client, _ := setupMockServer(t, commandCaptureHandler(t, &got))
+
+	for _, level := range []VacuumPowerLevel{-1, 4} {
+		err := client.SetVacuumPowerLevel(context.Background(), "V1", level)
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("SetVacuumPowerLevel(%d) error = %v; want ErrInvalidParameter", level, err)
+		}
+	}
+	if len(got) != 0 {
+		t.Errorf("sent %d commands for invalid levels; want 0", len(got))
+	}
+}
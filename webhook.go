@@ -2,9 +2,11 @@ package switchbot
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"time"
 )
 
 // WebhookSetupRequest is the request body for setting up a webhook.
@@ -25,11 +27,45 @@ func (c *Client) SetupWebhook(ctx context.Context, webhookURL string) error {
 		URL:        webhookURL,
 		DeviceList: "ALL", // Per documentation
 	}
-	path := fmt.Sprintf("/%s/webhook/setupWebhook", apiVersion)
+	path := fmt.Sprintf("/%s/webhook/setupWebhook", c.apiVersion)
 	_, err := c.doRequest(ctx, http.MethodPost, path, reqBody)
+	c.invalidateWebhookCache()
 	return err
 }
 
+// ErrWebhookNotRegistered is returned by SetupWebhookAndVerify when the URL does not appear in
+// QueryWebhookURL after setup.
+var ErrWebhookNotRegistered = errors.New("webhook not registered")
+
+// webhookVerifyAttempts is how many times SetupWebhookAndVerify queries the webhook URLs,
+// one second apart.
+const webhookVerifyAttempts = 5
+
+// SetupWebhookAndVerify calls SetupWebhook, then polls QueryWebhookURL until webhookURL is listed,
+// since the configuration may take a moment to propagate after setup reports success. It checks
+// up to five times, one second apart, and returns ErrWebhookNotRegistered if the URL is still
+// missing; use a ctx deadline to give up sooner.
+func (c *Client) SetupWebhookAndVerify(ctx context.Context, webhookURL string) error {
+	if err := c.SetupWebhook(ctx, webhookURL); err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		urls, err := c.QueryWebhookURL(ctx)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(urls, webhookURL) {
+			return nil
+		}
+		if attempt == webhookVerifyAttempts {
+			return fmt.Errorf("%w: %s not listed after %d queries", ErrWebhookNotRegistered, webhookURL, attempt)
+		}
+		if err := c.sleep(ctx, defaultCommandPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
 // WebhookQueryRequest is the request body for querying webhook configurations.
 type WebhookQueryRequest struct {
 	Action string   `json:"action"`         // "queryUrl" or "queryDetails"
@@ -47,43 +83,113 @@ type WebhookQueryURLResponse struct {
 type WebhookDetails struct {
 	URL            string `json:"url"`
 	DeviceList     string `json:"deviceList"`     // e.g., "ALL"
-	CreateTime     int64  `json:"createTime"`     // Unix timestamp (likely milliseconds)
-	LastUpdateTime int64  `json:"lastUpdateTime"` // Unix timestamp (likely milliseconds)
+	CreateTime     int64  `json:"createTime"`     // Unix timestamp in milliseconds; see CreatedAt
+	LastUpdateTime int64  `json:"lastUpdateTime"` // Unix timestamp in milliseconds; see LastUpdatedAt
 	Enable         bool   `json:"enable"`         // Whether the webhook is active
 	_              struct{}
 }
 
+// CreatedAt returns CreateTime as a time.Time, or the zero time if it is unset.
+func (d WebhookDetails) CreatedAt() time.Time {
+	return millisToTime(d.CreateTime)
+}
+
+// LastUpdatedAt returns LastUpdateTime as a time.Time, or the zero time if it is unset.
+func (d WebhookDetails) LastUpdatedAt() time.Time {
+	return millisToTime(d.LastUpdateTime)
+}
+
+// millisToTime converts a Unix timestamp in milliseconds to time.Time, mapping 0 to the zero time.
+func millisToTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
 // QueryWebhookURL retrieves the list of configured webhook URLs.
 func (c *Client) QueryWebhookURL(ctx context.Context) ([]string, error) {
 	reqBody := WebhookQueryRequest{Action: "queryUrl"}
-	path := fmt.Sprintf("/%s/webhook/queryWebhook", apiVersion)
-	resp, err := c.doRequest(ctx, http.MethodPost, path, reqBody)
+	path := fmt.Sprintf("/%s/webhook/queryWebhook", c.apiVersion)
+	resp, err := c.doRequest(withReadOnly(ctx), http.MethodPost, path, reqBody)
 	if err != nil {
 		return nil, err
 	}
 
 	var queryResp WebhookQueryURLResponse
-	if err := json.Unmarshal(resp.Body, &queryResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal QueryWebhookURL response body: %w, body: %s", err, string(resp.Body))
+	if err := decodeBody(resp.Body, &queryResp); err != nil {
+		return nil, fmt.Errorf("QueryWebhookURL response: %w", err)
 	}
 	return queryResp.URLs, nil
 }
 
+// DefaultWebhookBatchSize is how many URLs QueryWebhookDetails sends per request by default.
+const DefaultWebhookBatchSize = 10
+
+// WithWebhookBatchSize sets how many URLs QueryWebhookDetails sends per request.
+func WithWebhookBatchSize(n int) ClientOption {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("webhook batch size must be positive, got %d", n)
+		}
+		c.webhookBatchSize = n
+		return nil
+	}
+}
+
 // QueryWebhookDetails retrieves the detailed configuration for the specified webhook URLs.
+// URLs are queried in batches (see WithWebhookBatchSize) and the results are merged in the
+// order of urls. A failing batch does not stop the others: the details from successful batches
+// are returned together with an error joining each batch's failure.
 func (c *Client) QueryWebhookDetails(ctx context.Context, urls []string) ([]WebhookDetails, error) {
 	if len(urls) == 0 {
 		return nil, fmt.Errorf("at least one URL must be provided for queryDetails")
 	}
+
+	var details []WebhookDetails
+	var errs []error
+	for batch := range slices.Chunk(urls, c.webhookBatchSize) {
+		batchDetails, err := c.queryWebhookDetailsBatch(ctx, batch)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to query webhook details for %q: %w", batch, err))
+			continue
+		}
+		details = append(details, batchDetails...)
+	}
+
+	// Order the results like the input; details for URLs not requested go last.
+	position := make(map[string]int, len(urls))
+	for i, u := range urls {
+		if _, ok := position[u]; !ok {
+			position[u] = i
+		}
+	}
+	slices.SortStableFunc(details, func(a, b WebhookDetails) int {
+		pa, ok := position[a.URL]
+		if !ok {
+			pa = len(urls)
+		}
+		pb, ok := position[b.URL]
+		if !ok {
+			pb = len(urls)
+		}
+		return pa - pb
+	})
+	return details, errors.Join(errs...)
+}
+
+// queryWebhookDetailsBatch sends a single queryDetails request.
+func (c *Client) queryWebhookDetailsBatch(ctx context.Context, urls []string) ([]WebhookDetails, error) {
 	reqBody := WebhookQueryRequest{Action: "queryDetails", URLs: urls}
-	path := fmt.Sprintf("/%s/webhook/queryWebhook", apiVersion)
-	resp, err := c.doRequest(ctx, http.MethodPost, path, reqBody)
+	path := fmt.Sprintf("/%s/webhook/queryWebhook", c.apiVersion)
+	resp, err := c.doRequest(withReadOnly(ctx), http.MethodPost, path, reqBody)
 	if err != nil {
 		return nil, err
 	}
 
 	var details []WebhookDetails
-	if err := json.Unmarshal(resp.Body, &details); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal QueryWebhookDetails response body: %w, body: %s", err, string(resp.Body))
+	if err := decodeBody(resp.Body, &details); err != nil {
+		return nil, fmt.Errorf("QueryWebhookDetails response: %w", err)
 	}
 	return details, nil
 }
@@ -114,8 +220,9 @@ func (c *Client) UpdateWebhook(ctx context.Context, webhookURL string, enable bo
 			Enable: enable,
 		},
 	}
-	path := fmt.Sprintf("/%s/webhook/updateWebhook", apiVersion)
+	path := fmt.Sprintf("/%s/webhook/updateWebhook", c.apiVersion)
 	_, err := c.doRequest(ctx, http.MethodPost, path, reqBody)
+	c.invalidateWebhookCache()
 	return err
 }
 
@@ -135,7 +242,76 @@ func (c *Client) DeleteWebhook(ctx context.Context, webhookURL string) error {
 		Action: "deleteWebhook",
 		URL:    webhookURL,
 	}
-	path := fmt.Sprintf("/%s/webhook/deleteWebhook", apiVersion)
+	path := fmt.Sprintf("/%s/webhook/deleteWebhook", c.apiVersion)
 	_, err := c.doRequest(ctx, http.MethodPost, path, reqBody)
+	c.invalidateWebhookCache()
 	return err
 }
+
+// DeleteAllWebhooks removes every configured webhook URL. It is a no-op when none are configured.
+// Deletion continues past individual failures; the returned error joins all of them.
+func (c *Client) DeleteAllWebhooks(ctx context.Context) error {
+	urls, err := c.QueryWebhookURL(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, u := range urls {
+		if err := c.DeleteWebhook(ctx, u); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete webhook %s: %w", u, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// defaultWebhookCacheTTL is how long CachedWebhookDetails results are cached.
+const defaultWebhookCacheTTL = 5 * time.Minute
+
+// CachedWebhookDetails returns the details of every configured webhook URL, combining
+// QueryWebhookURL and QueryWebhookDetails. Results are cached for five minutes; SetupWebhook,
+// UpdateWebhook and DeleteWebhook invalidate the cache. A fetch that overlaps an invalidation
+// returns its result without caching it.
+func (c *Client) CachedWebhookDetails(ctx context.Context) ([]WebhookDetails, error) {
+	c.mu.Lock()
+	if c.webhookCache != nil && time.Now().Before(c.webhookCacheExpiry) {
+		cached := slices.Clone(c.webhookCache)
+		c.mu.Unlock()
+		return cached, nil
+	}
+	gen := c.webhookCacheGen
+	c.mu.Unlock()
+
+	urls, err := c.QueryWebhookURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	details := []WebhookDetails{}
+	if len(urls) > 0 {
+		details, err = c.QueryWebhookDetails(ctx, urls)
+		if err != nil {
+			return nil, err
+		}
+		if details == nil {
+			details = []WebhookDetails{}
+		}
+	}
+
+	c.mu.Lock()
+	if c.webhookCacheGen == gen {
+		c.webhookCache = slices.Clone(details)
+		c.webhookCacheExpiry = time.Now().Add(c.webhookCacheTTL)
+	}
+	c.mu.Unlock()
+
+	return details, nil
+}
+
+// invalidateWebhookCache discards the cached CachedWebhookDetails result and any fetch in flight.
+func (c *Client) invalidateWebhookCache() {
+	c.mu.Lock()
+	c.webhookCacheGen++
+	c.webhookCache = nil
+	c.webhookCacheExpiry = time.Time{}
+	c.mu.Unlock()
+}
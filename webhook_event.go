@@ -0,0 +1,83 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Device types reported in the context of webhook events. These differ from the deviceType
+// values returned by the device list.
+const (
+	WebhookDeviceTypeKeypad      = "WoKeypad"
+	WebhookDeviceTypeKeypadTouch = "WoKeypadTouch"
+)
+
+// WebhookEvent is the payload SwitchBot POSTs to a configured webhook URL.
+// Context holds the device-specific fields; decode it with a typed accessor such as AsKeypad.
+type WebhookEvent struct {
+	EventType    string          `json:"eventType"`    // e.g. "changeReport"
+	EventVersion string          `json:"eventVersion"` // e.g. "1"
+	Context      json.RawMessage `json:"context"`
+	_            struct{}
+}
+
+// ParseWebhookEvent decodes a webhook request body.
+func ParseWebhookEvent(data []byte) (*WebhookEvent, error) {
+	var event WebhookEvent
+	if err := decodeBody(data, &event); err != nil {
+		return nil, fmt.Errorf("webhook event: %w", err)
+	}
+	return &event, nil
+}
+
+// DeviceType returns the deviceType from the event context, or "" if it is missing.
+func (e *WebhookEvent) DeviceType() string {
+	var header struct {
+		DeviceType string `json:"deviceType"`
+	}
+	_ = json.Unmarshal(e.Context, &header)
+	return header.DeviceType
+}
+
+// KeypadEventName is the subtype of a Keypad webhook event. The API reference documents only
+// the createKey and deleteKey results; any other eventName is passed through unchanged.
+type KeypadEventName string
+
+const (
+	KeypadEventCreateKey KeypadEventName = "createKey" // Result of an asynchronous createKey command
+	KeypadEventDeleteKey KeypadEventName = "deleteKey" // Result of an asynchronous deleteKey command
+)
+
+// String implements fmt.Stringer.
+func (n KeypadEventName) String() string {
+	if n == "" {
+		return "unknown"
+	}
+	return string(n)
+}
+
+// KeypadWebhookContext is the context of a Keypad or Keypad Touch webhook event.
+type KeypadWebhookContext struct {
+	DeviceType   string          `json:"deviceType"`
+	DeviceMac    string          `json:"deviceMac"`
+	EventName    KeypadEventName `json:"eventName"`
+	CommandID    string          `json:"commandId,omitempty"` // Set for createKey/deleteKey results
+	Result       string          `json:"result,omitempty"`    // "success" or "failed" for createKey/deleteKey
+	TimeOfSample int64           `json:"timeOfSample"`        // Unix timestamp in milliseconds
+	_            struct{}
+}
+
+// AsKeypad decodes the event context as a Keypad event.
+// Returns ErrDeviceTypeMismatch if the event was not sent by a Keypad or Keypad Touch.
+func (e *WebhookEvent) AsKeypad() (*KeypadWebhookContext, error) {
+	deviceType := e.DeviceType()
+	if !slices.Contains([]string{WebhookDeviceTypeKeypad, WebhookDeviceTypeKeypadTouch}, deviceType) {
+		return nil, fmt.Errorf("%w: webhook event is from %q, want a Keypad", ErrDeviceTypeMismatch, deviceType)
+	}
+	var keypad KeypadWebhookContext
+	if err := decodeBody(e.Context, &keypad); err != nil {
+		return nil, fmt.Errorf("Keypad webhook context: %w", err)
+	}
+	return &keypad, nil
+}
@@ -0,0 +1,71 @@
+package switchbot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWebhookEventAsKeypad(t *testing.T) {
+	testCases := []struct {
+		name          string
+		payload       string
+		wantEvent     KeypadEventName
+		wantCommandID string
+	}{
+		{
+			name:          "CreateKey",
+			payload:       `{"eventType":"changeReport","eventVersion":"1","context":{"deviceType":"WoKeypad","deviceMac":"01:00:5e:90:10:00","eventName":"createKey","commandId":"CMD-1663558451952-01","result":"success","timeOfSample":1663558451952}}`,
+			wantEvent:     KeypadEventCreateKey,
+			wantCommandID: "CMD-1663558451952-01",
+		},
+		{
+			name:          "DeleteKey",
+			payload:       `{"eventType":"changeReport","eventVersion":"1","context":{"deviceType":"WoKeypadTouch","deviceMac":"01:00:5e:90:10:01","eventName":"deleteKey","commandId":"CMD-1663558451952-02","result":"failed","timeOfSample":1663558451953}}`,
+			wantEvent:     KeypadEventDeleteKey,
+			wantCommandID: "CMD-1663558451952-02",
+		},
+		{
+			name:      "UnknownEventPassedThrough",
+			payload:   `{"eventType":"changeReport","eventVersion":"1","context":{"deviceType":"WoKeypad","deviceMac":"01:00:5e:90:10:00","eventName":"someFutureEvent","timeOfSample":1700000000000}}`,
+			wantEvent: KeypadEventName("someFutureEvent"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := ParseWebhookEvent([]byte(tc.payload))
+			if err != nil {
+				t.Fatalf("ParseWebhookEvent() returned error: %v", err)
+			}
+			keypad, err := event.AsKeypad()
+			if err != nil {
+				t.Fatalf("AsKeypad() returned error: %v", err)
+			}
+			if keypad.EventName != tc.wantEvent {
+				t.Errorf("EventName = %s; want %s", keypad.EventName, tc.wantEvent)
+			}
+			if keypad.CommandID != tc.wantCommandID {
+				t.Errorf("CommandID = %q; want %q", keypad.CommandID, tc.wantCommandID)
+			}
+			if keypad.DeviceMac == "" || keypad.TimeOfSample == 0 {
+				t.Errorf("context not fully decoded: %+v", *keypad)
+			}
+		})
+	}
+
+	t.Run("NotKeypad", func(t *testing.T) {
+		event, err := ParseWebhookEvent([]byte(`{"eventType":"changeReport","eventVersion":"1","context":{"deviceType":"WoMeter","deviceMac":"01:00:5e:90:10:02","temperature":22.5,"timeOfSample":1}}`))
+		if err != nil {
+			t.Fatalf("ParseWebhookEvent() returned error: %v", err)
+		}
+		if _, err := event.AsKeypad(); !errors.Is(err, ErrDeviceTypeMismatch) {
+			t.Errorf("AsKeypad() error = %v; want ErrDeviceTypeMismatch", err)
+		}
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		if _, err := ParseWebhookEvent([]byte(`not json`)); err == nil {
+			t.Error("ParseWebhookEvent() returned nil error for malformed payload")
+		}
+	})
+}
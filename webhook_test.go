@@ -0,0 +1,348 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookServer is a minimal in-memory implementation of the webhook endpoints.
+type webhookServer struct {
+	mu      sync.Mutex
+	details []WebhookDetails
+	actions []string
+	// failDelete lists URLs whose deleteWebhook request fails with statusCode 190.
+	failDelete map[string]bool
+	// hideQueries is how many queryUrl requests report no webhooks, simulating propagation delay.
+	hideQueries int
+	// beforeDetails, if set, is called when a queryDetails request arrives.
+	beforeDetails func()
+}
+
+func (s *webhookServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode webhook request: %v", err)
+		}
+		action, _ := req["action"].(string)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.actions = append(s.actions, action)
+
+		var body interface{} = struct{}{}
+		switch action {
+		case "queryUrl":
+			urls := []string{}
+			for _, d := range s.details {
+				urls = append(urls, d.URL)
+			}
+			if s.hideQueries > 0 {
+				s.hideQueries--
+				urls = []string{}
+			}
+			body = map[string]interface{}{"urls": urls}
+		case "queryDetails":
+			if s.beforeDetails != nil {
+				s.beforeDetails()
+			}
+			body = s.details
+		case "setupWebhook":
+			s.details = append(s.details, WebhookDetails{URL: req["url"].(string), DeviceList: "ALL", Enable: true})
+		case "deleteWebhook":
+			url, _ := req["url"].(string)
+			if s.failDelete[url] {
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, `{"statusCode": 190, "message": "Device internal error", "body": {}}`)
+				return
+			}
+			s.details = slices.DeleteFunc(s.details, func(d WebhookDetails) bool { return d.URL == url })
+		}
+		b, _ := json.Marshal(body)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"statusCode": 100, "message": "success", "body": %s}`, b)
+	}
+}
+
+func (s *webhookServer) actionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.actions)
+}
+
+func TestCachedWebhookDetails(t *testing.T) {
+	server := &webhookServer{details: []WebhookDetails{{URL: "https://example.com/hook", DeviceList: "ALL", Enable: true}}}
+	client, _ := setupMockServer(t, server.handler(t))
+	ctx := context.Background()
+
+	t.Run("CombinedFetch", func(t *testing.T) {
+		details, err := client.CachedWebhookDetails(ctx)
+		if err != nil {
+			t.Fatalf("CachedWebhookDetails() returned error: %v", err)
+		}
+		if len(details) != 1 || details[0].URL != "https://example.com/hook" {
+			t.Errorf("CachedWebhookDetails() = %+v; want one entry for https://example.com/hook", details)
+		}
+		if got := server.actionCount(); got != 2 {
+			t.Errorf("made %d requests; want 2 (queryUrl + queryDetails)", got)
+		}
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		before := server.actionCount()
+		if _, err := client.CachedWebhookDetails(ctx); err != nil {
+			t.Fatalf("CachedWebhookDetails() returned error: %v", err)
+		}
+		if got := server.actionCount(); got != before {
+			t.Errorf("cached CachedWebhookDetails() made %d requests; want 0", got-before)
+		}
+	})
+
+	t.Run("InvalidatedByDelete", func(t *testing.T) {
+		if err := client.DeleteWebhook(ctx, "https://example.com/hook"); err != nil {
+			t.Fatalf("DeleteWebhook() returned error: %v", err)
+		}
+		details, err := client.CachedWebhookDetails(ctx)
+		if err != nil {
+			t.Fatalf("CachedWebhookDetails() returned error: %v", err)
+		}
+		if len(details) != 0 {
+			t.Errorf("CachedWebhookDetails() after delete = %+v; want empty", details)
+		}
+	})
+
+	t.Run("InvalidatedBySetup", func(t *testing.T) {
+		if err := client.SetupWebhook(ctx, "https://example.com/new"); err != nil {
+			t.Fatalf("SetupWebhook() returned error: %v", err)
+		}
+		details, err := client.CachedWebhookDetails(ctx)
+		if err != nil {
+			t.Fatalf("CachedWebhookDetails() returned error: %v", err)
+		}
+		if len(details) != 1 || details[0].URL != "https://example.com/new" {
+			t.Errorf("CachedWebhookDetails() after setup = %+v; want https://example.com/new", details)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		server := &webhookServer{details: []WebhookDetails{{URL: "https://example.com/hook"}}}
+		client, _ := setupMockServer(t, server.handler(t), withWebhookCacheTTL(0))
+		if _, err := client.CachedWebhookDetails(ctx); err != nil {
+			t.Fatalf("CachedWebhookDetails() returned error: %v", err)
+		}
+		if _, err := client.CachedWebhookDetails(ctx); err != nil {
+			t.Fatalf("CachedWebhookDetails() returned error: %v", err)
+		}
+		if got := server.actionCount(); got != 4 {
+			t.Errorf("made %d requests with zero TTL; want 4", got)
+		}
+	})
+
+	t.Run("StaleFetchNotCached", func(t *testing.T) {
+		server := &webhookServer{details: []WebhookDetails{{URL: "https://example.com/hook"}}}
+		client, _ := setupMockServer(t, server.handler(t))
+		// An invalidation lands while the fetch is between queryUrl and queryDetails
+		server.beforeDetails = client.invalidateWebhookCache
+		if _, err := client.CachedWebhookDetails(ctx); err != nil {
+			t.Fatalf("CachedWebhookDetails() returned error: %v", err)
+		}
+		before := server.actionCount()
+		if _, err := client.CachedWebhookDetails(ctx); err != nil {
+			t.Fatalf("CachedWebhookDetails() returned error: %v", err)
+		}
+		if got := server.actionCount() - before; got != 2 {
+			t.Errorf("made %d requests after an overlapping invalidation; want 2 (result not cached)", got)
+		}
+	})
+}
+
+func TestDeleteAllWebhooks(t *testing.T) {
+	t.Run("DeletesEvery", func(t *testing.T) {
+		server := &webhookServer{details: []WebhookDetails{{URL: "https://example.com/a"}, {URL: "https://example.com/b"}}}
+		client, _ := setupMockServer(t, server.handler(t))
+
+		if err := client.DeleteAllWebhooks(context.Background()); err != nil {
+			t.Fatalf("DeleteAllWebhooks() returned error: %v", err)
+		}
+		if len(server.details) != 0 {
+			t.Errorf("remaining webhooks = %v; want none", server.details)
+		}
+	})
+
+	t.Run("NoWebhooks", func(t *testing.T) {
+		server := &webhookServer{}
+		client, _ := setupMockServer(t, server.handler(t))
+
+		if err := client.DeleteAllWebhooks(context.Background()); err != nil {
+			t.Fatalf("DeleteAllWebhooks() returned error: %v", err)
+		}
+		if server.actionCount() != 1 {
+			t.Errorf("made %d requests; want only the query", server.actionCount())
+		}
+	})
+
+	t.Run("PartialFailure", func(t *testing.T) {
+		server := &webhookServer{
+			details:    []WebhookDetails{{URL: "https://example.com/a"}, {URL: "https://example.com/b"}, {URL: "https://example.com/c"}},
+			failDelete: map[string]bool{"https://example.com/a": true},
+		}
+		client, _ := setupMockServer(t, server.handler(t))
+
+		err := client.DeleteAllWebhooks(context.Background())
+		if !errors.Is(err, ErrDeviceInternal) {
+			t.Fatalf("DeleteAllWebhooks() error = %v; want ErrDeviceInternal", err)
+		}
+		if len(server.details) != 1 || server.details[0].URL != "https://example.com/a" {
+			t.Errorf("remaining webhooks = %v; want only the failed one", server.details)
+		}
+	})
+}
+
+func TestSetupWebhookAndVerify(t *testing.T) {
+	noSleep := func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	const url = "https://example.com/hook"
+
+	t.Run("AfterPropagation", func(t *testing.T) {
+		server := &webhookServer{hideQueries: 2}
+		client, _ := setupMockServer(t, server.handler(t), WithSleeper(noSleep))
+
+		if err := client.SetupWebhookAndVerify(context.Background(), url); err != nil {
+			t.Fatalf("SetupWebhookAndVerify() returned error: %v", err)
+		}
+		want := []string{"setupWebhook", "queryUrl", "queryUrl", "queryUrl"}
+		if !slices.Equal(server.actions, want) {
+			t.Errorf("actions = %v; want %v", server.actions, want)
+		}
+	})
+
+	t.Run("NeverListed", func(t *testing.T) {
+		server := &webhookServer{hideQueries: 100}
+		client, _ := setupMockServer(t, server.handler(t), WithSleeper(noSleep))
+
+		if err := client.SetupWebhookAndVerify(context.Background(), url); !errors.Is(err, ErrWebhookNotRegistered) {
+			t.Errorf("SetupWebhookAndVerify() error = %v; want ErrWebhookNotRegistered", err)
+		}
+		if got := server.actionCount(); got != 1+webhookVerifyAttempts {
+			t.Errorf("made %d requests; want setup plus %d queries", got, webhookVerifyAttempts)
+		}
+	})
+}
+
+func TestWebhookDetailsTimes(t *testing.T) {
+	d := WebhookDetails{CreateTime: 1700000000123, LastUpdateTime: 1700000600000}
+	if got, want := d.CreatedAt(), time.UnixMilli(1700000000123); !got.Equal(want) {
+		t.Errorf("CreatedAt() = %v; want %v", got, want)
+	}
+	if got, want := d.LastUpdatedAt(), time.Date(2023, 11, 14, 22, 23, 20, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("LastUpdatedAt() = %v; want %v", got, want)
+	}
+	if !(WebhookDetails{}).CreatedAt().IsZero() {
+		t.Error("CreatedAt() of unset CreateTime is not the zero time")
+	}
+}
+
+func TestQueryWebhookDetails_Batching(t *testing.T) {
+	urls := make([]string, 25)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://example.com/hook/%02d", i)
+	}
+
+	var batches [][]string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var req WebhookQueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode webhook request: %v", err)
+		}
+		batches = append(batches, req.URLs)
+		w.WriteHeader(http.StatusOK)
+		if slices.Contains(req.URLs, "https://example.com/hook/12") {
+			fmt.Fprintln(w, `{"statusCode": 190, "message": "Device internal error", "body": {}}`)
+			return
+		}
+		// Reply in reverse order to check that results follow the input order.
+		details := []WebhookDetails{}
+		for _, u := range slices.Backward(req.URLs) {
+			details = append(details, WebhookDetails{URL: u, DeviceList: "ALL", Enable: true})
+		}
+		body, _ := json.Marshal(details)
+		fmt.Fprintf(w, `{"statusCode": 100, "message": "success", "body": %s}`, body)
+	}
+	client, _ := setupMockServer(t, handler)
+
+	details, err := client.QueryWebhookDetails(context.Background(), urls)
+	if !errors.Is(err, ErrDeviceInternal) || !strings.Contains(err.Error(), "hook/12") {
+		t.Errorf("QueryWebhookDetails() error = %v; want the failed batch reported", err)
+	}
+	if len(batches) != 3 || len(batches[0]) != 10 || len(batches[1]) != 10 || len(batches[2]) != 5 {
+		t.Fatalf("sent batches of sizes %d; want 10, 10, 5", len(batches))
+	}
+
+	want := append(slices.Clone(urls[:10]), urls[20:]...)
+	if len(details) != len(want) {
+		t.Fatalf("QueryWebhookDetails() returned %d details; want %d", len(details), len(want))
+	}
+	for i, d := range details {
+		if d.URL != want[i] {
+			t.Errorf("details[%d].URL = %s; want %s", i, d.URL, want[i])
+		}
+	}
+
+	t.Run("CustomBatchSize", func(t *testing.T) {
+		batches = nil
+		client, _ := setupMockServer(t, handler, WithWebhookBatchSize(4))
+		if _, err := client.QueryWebhookDetails(context.Background(), urls[:9]); err != nil {
+			t.Fatalf("QueryWebhookDetails() returned error: %v", err)
+		}
+		if len(batches) != 3 {
+			t.Errorf("sent %d batches; want 3", len(batches))
+		}
+		if _, err := NewClient("token", "secret", WithWebhookBatchSize(0)); err == nil {
+			t.Error("WithWebhookBatchSize(0) did not return an error")
+		}
+	})
+}
+
+// withWebhookCacheTTL overrides how long CachedWebhookDetails results are cached.
+func withWebhookCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.webhookCacheTTL = ttl
+		return nil
+	}
+}
+
+func TestWebhookQueriesInDryRun(t *testing.T) {
+	server := &webhookServer{details: []WebhookDetails{{URL: "https://example.com/hook", DeviceList: "ALL", Enable: true}}}
+	client, _ := setupMockServer(t, server.handler(t), WithDryRun(true))
+	ctx := context.Background()
+
+	urls, err := client.QueryWebhookURL(ctx)
+	if err != nil {
+		t.Fatalf("QueryWebhookURL() returned error: %v", err)
+	}
+	if len(urls) != 1 || urls[0] != "https://example.com/hook" {
+		t.Errorf("QueryWebhookURL() = %v; want [https://example.com/hook]", urls)
+	}
+	details, err := client.CachedWebhookDetails(ctx)
+	if err != nil {
+		t.Fatalf("CachedWebhookDetails() returned error: %v", err)
+	}
+	if len(details) != 1 || details[0].URL != "https://example.com/hook" {
+		t.Errorf("CachedWebhookDetails() = %+v; want one entry for https://example.com/hook", details)
+	}
+
+	// Changes are still skipped
+	if err := client.SetupWebhook(ctx, "https://example.com/new"); !errors.Is(err, ErrDryRun) {
+		t.Errorf("SetupWebhook() error = %v; want ErrDryRun", err)
+	}
+	if got := server.actionCount(); got != 3 {
+		t.Errorf("server received %d requests; want 3 (queryUrl, queryUrl + queryDetails)", got)
+	}
+}